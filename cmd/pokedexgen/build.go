@@ -0,0 +1,511 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	pokemon "pokemon/pkg"
+)
+
+// PokeAPI ids that aren't looked up from a table.
+const (
+	levelUpMoveMethod = 1
+	femaleGenderID    = 1
+	maleGenderID      = 2
+)
+
+var growthRates = map[string]pokemon.GrowthRate{
+	"slow":                pokemon.Slow,
+	"medium":              pokemon.MediumFast,
+	"fast":                pokemon.Fast,
+	"medium-slow":         pokemon.MediumSlow,
+	"slow-then-very-fast": pokemon.Erratic,
+	"fast-then-very-slow": pokemon.Fluctuating,
+}
+
+var stats = map[string]pokemon.Stat{
+	"hp":              pokemon.StatHP,
+	"attack":          pokemon.StatAttack,
+	"defense":         pokemon.StatDefense,
+	"special-attack":  pokemon.StatSpecialAttack,
+	"special-defense": pokemon.StatSpecialDefense,
+	"speed":           pokemon.StatSpeed,
+	"accuracy":        pokemon.StatAccuracy,
+	"evasion":         pokemon.StatEvasion,
+}
+
+var moveCategories = map[int]pokemon.MoveCategory{
+	1: pokemon.Status,
+	2: pokemon.Physical,
+	3: pokemon.Special,
+}
+
+// builder turns the tables of a PokeAPI CSV dump into species and moves. Anything
+// that can't be represented is skipped and noted in warnings.
+type builder struct {
+	dir      string
+	warnings []string
+
+	types     map[int]pokemon.Type
+	items     map[int]string
+	locations map[int]string
+	moveNames map[int]string
+}
+
+func newBuilder(dir string) *builder {
+	return &builder{dir: dir}
+}
+
+func (b *builder) warnf(format string, args ...interface{}) {
+	b.warnings = append(b.warnings, fmt.Sprintf(format, args...))
+}
+
+func (b *builder) table(name string) ([]row, error) {
+	return readTable(b.dir, name, false)
+}
+
+func (b *builder) optionalTable(name string) ([]row, error) {
+	return readTable(b.dir, name, true)
+}
+
+// namedIdentifiers maps ids to English names, falling back to the identifier for
+// anything the names table doesn't cover.
+func (b *builder) namedIdentifiers(table, namesTable, idColumn string) (map[int]string, error) {
+	rows, err := b.table(table)
+	if err != nil {
+		return nil, err
+	}
+	nameRows, err := b.optionalTable(namesTable)
+	if err != nil {
+		return nil, err
+	}
+
+	names := englishNames(nameRows, idColumn)
+	for id, identifier := range identifiers(rows) {
+		if _, ok := names[id]; !ok {
+			names[id] = title(identifier)
+		}
+	}
+	return names, nil
+}
+
+func (b *builder) loadTypes() error {
+	rows, err := b.table("types.csv")
+	if err != nil {
+		return err
+	}
+
+	b.types = make(map[int]pokemon.Type)
+	for id, identifier := range identifiers(rows) {
+		typ, err := pokemon.StringToPokemonType(identifier)
+		if err != nil {
+			continue // shadow and unknown have no place in the type chart
+		}
+		b.types[id] = typ
+	}
+	return nil
+}
+
+// Moves builds every move in the dump, sorted by ID, with the effects declared in
+// the move_meta tables.
+func (b *builder) Moves() ([]pokemon.Move, error) {
+	if err := b.loadTypes(); err != nil {
+		return nil, err
+	}
+	rows, err := b.table("moves.csv")
+	if err != nil {
+		return nil, err
+	}
+	if b.moveNames, err = b.namedIdentifiers("moves.csv", "move_names.csv", "move_id"); err != nil {
+		return nil, err
+	}
+
+	effects, err := b.moveEffects(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].int("id") < rows[j].int("id") })
+
+	var moves []pokemon.Move
+	for _, r := range rows {
+		typ, ok := b.types[r.int("type_id")]
+		if !ok {
+			b.warnf("move %s: unsupported type %s", r["identifier"], r["type_id"])
+			continue
+		}
+
+		moves = append(moves, pokemon.Move{
+			ID:          r.int("id"),
+			Name:        b.moveNames[r.int("id")],
+			Type:        typ,
+			Category:    moveCategories[r.int("damage_class_id")],
+			Power:       r.int("power"),
+			PP:          r.int("pp"),
+			Accuracy:    r.int("accuracy"), // empty for moves that never miss
+			Priority:    r.int("priority"),
+			TargetsUser: userTargets[r.int("target_id")],
+			EffectSpecs: effects[r.int("id")],
+		})
+	}
+	return moves, nil
+}
+
+// Species builds every species in the dump from its default form, sorted by ID.
+// Moves has to be called first so learnsets can refer to move names.
+func (b *builder) Species() ([]pokemon.Species, error) {
+	speciesRows, err := b.table("pokemon_species.csv")
+	if err != nil {
+		return nil, err
+	}
+	speciesNames, err := b.namedIdentifiers("pokemon_species.csv", "pokemon_species_names.csv", "pokemon_species_id")
+	if err != nil {
+		return nil, err
+	}
+	rateRows, err := b.table("growth_rates.csv")
+	if err != nil {
+		return nil, err
+	}
+	rates := identifiers(rateRows)
+
+	pokemonRows, err := b.table("pokemon.csv")
+	if err != nil {
+		return nil, err
+	}
+	// species ID -> the pokemon row of its default form
+	forms := make(map[int]row)
+	for _, r := range pokemonRows {
+		if r.int("is_default") == 1 {
+			forms[r.int("species_id")] = r
+		}
+	}
+
+	types, err := b.pokemonTypes()
+	if err != nil {
+		return nil, err
+	}
+	baseStats, effort, err := b.pokemonStats()
+	if err != nil {
+		return nil, err
+	}
+	learnsets, err := b.learnsets()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(speciesRows, func(i, j int) bool { return speciesRows[i].int("id") < speciesRows[j].int("id") })
+
+	var species []pokemon.Species
+	index := make(map[int]int) // species ID -> index in species
+	for _, r := range speciesRows {
+		id := r.int("id")
+		form, ok := forms[id]
+		if !ok {
+			b.warnf("species %s: no default form", r["identifier"])
+			continue
+		}
+		pokemonID := form.int("id")
+
+		rate, ok := growthRates[rates[r.int("growth_rate_id")]]
+		if !ok {
+			b.warnf("species %s: unknown growth rate %s", r["identifier"], r["growth_rate_id"])
+		}
+
+		index[id] = len(species)
+		species = append(species, pokemon.Species{
+			ID:           id,
+			Name:         speciesNames[id],
+			Types:        types[pokemonID],
+			BaseStats:    baseStats[pokemonID],
+			BaseExpYield: form.int("base_experience"),
+			GrowthRate:   rate,
+			EVYield:      effort[pokemonID],
+			CaptureRate:  r.int("capture_rate"),
+			Learnset:     learnsets[pokemonID],
+		})
+	}
+
+	evolvesFrom := make(map[int]int)
+	for _, r := range speciesRows {
+		evolvesFrom[r.int("id")] = r.int("evolves_from_species_id")
+	}
+	if err := b.addEvolutions(species, index, evolvesFrom); err != nil {
+		return nil, err
+	}
+	return species, nil
+}
+
+// pokemonTypes maps pokemon IDs to their types in slot order.
+func (b *builder) pokemonTypes() (map[int][]pokemon.Type, error) {
+	rows, err := b.table("pokemon_types.csv")
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].int("slot") < rows[j].int("slot") })
+
+	types := make(map[int][]pokemon.Type)
+	for _, r := range rows {
+		if typ, ok := b.types[r.int("type_id")]; ok {
+			types[r.int("pokemon_id")] = append(types[r.int("pokemon_id")], typ)
+		}
+	}
+	return types, nil
+}
+
+// pokemonStats maps pokemon IDs to their base stats and EV yield.
+func (b *builder) pokemonStats() (map[int]pokemon.Stats, map[int]pokemon.Stats, error) {
+	statRows, err := b.table("stats.csv")
+	if err != nil {
+		return nil, nil, err
+	}
+	statNames := identifiers(statRows)
+
+	rows, err := b.table("pokemon_stats.csv")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	baseStats := make(map[int]pokemon.Stats)
+	effort := make(map[int]pokemon.Stats)
+	for _, r := range rows {
+		stat, ok := stats[statNames[r.int("stat_id")]]
+		if !ok {
+			continue
+		}
+		id := r.int("pokemon_id")
+		base, ev := baseStats[id], effort[id]
+		base.Set(stat, r.int("base_stat"))
+		ev.Set(stat, r.int("effort"))
+		baseStats[id], effort[id] = base, ev
+	}
+	return baseStats, effort, nil
+}
+
+// learnsets maps pokemon IDs to the moves they learn by level up in the latest
+// version group that has any for them.
+func (b *builder) learnsets() (map[int]pokemon.Learnset, error) {
+	rows, err := b.table("pokemon_moves.csv")
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[int]int) // pokemon ID -> version group
+	var levelUp []row
+	for _, r := range rows {
+		if r.int("pokemon_move_method_id") != levelUpMoveMethod {
+			continue
+		}
+		levelUp = append(levelUp, r)
+		if vg := r.int("version_group_id"); vg > latest[r.int("pokemon_id")] {
+			latest[r.int("pokemon_id")] = vg
+		}
+	}
+	sort.SliceStable(levelUp, func(i, j int) bool {
+		if levelUp[i].int("level") != levelUp[j].int("level") {
+			return levelUp[i].int("level") < levelUp[j].int("level")
+		}
+		return levelUp[i].int("order") < levelUp[j].int("order")
+	})
+
+	learnsets := make(map[int]pokemon.Learnset)
+	for _, r := range levelUp {
+		id := r.int("pokemon_id")
+		if r.int("version_group_id") != latest[id] {
+			continue
+		}
+		name, ok := b.moveNames[r.int("move_id")]
+		if !ok {
+			b.warnf("pokemon %d: unknown move %s", id, r["move_id"])
+			continue
+		}
+		learnsets[id] = append(learnsets[id], pokemon.LearnableMove{Level: r.int("level"), Move: pokemon.Move{Name: name}})
+	}
+	return learnsets, nil
+}
+
+func (b *builder) addEvolutions(species []pokemon.Species, index map[int]int, evolvesFrom map[int]int) error {
+	rows, err := b.table("pokemon_evolution.csv")
+	if err != nil {
+		return err
+	}
+	triggerRows, err := b.table("evolution_triggers.csv")
+	if err != nil {
+		return err
+	}
+	triggers := identifiers(triggerRows)
+
+	itemRows, err := b.table("items.csv")
+	if err != nil {
+		return err
+	}
+	b.items = identifiers(itemRows)
+	// locations are only needed by a few evolutions, so a dump may leave them out
+	if b.locations, err = b.namedIdentifiers("locations.csv", "location_names.csv", "location_id"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].int("id") < rows[j].int("id") })
+
+	for _, r := range rows {
+		into := r.int("evolved_species_id")
+		from, ok := index[evolvesFrom[into]]
+		if !ok {
+			b.warnf("evolution %s: species %d doesn't evolve from anything known", r["id"], into)
+			continue
+		}
+		if _, ok := index[into]; !ok {
+			b.warnf("evolution %s: unknown species %d", r["id"], into)
+			continue
+		}
+
+		method, err := b.evolutionMethod(r, triggers[r.int("evolution_trigger_id")])
+		if err != nil {
+			b.warnf("%s into %s: %v", species[from].Name, species[index[into]].Name, err)
+			continue
+		}
+		species[from].EvolutionStages = append(species[from].EvolutionStages, pokemon.NewEvolutionStage(into, method))
+	}
+	return nil
+}
+
+func (b *builder) evolutionMethod(r row, trigger string) (pokemon.EvolutionMethod, error) {
+	switch trigger {
+	case "level-up":
+		return b.levelUpEvolution(r)
+	case "trade":
+		if err := unsupported(r, "minimum_level", "gender_id", "minimum_happiness", "known_move_id", "location_id", "time_of_day"); err != nil {
+			return nil, err
+		}
+		method := pokemon.TradeEvolution{TradedWith: r.int("trade_species_id")}
+		if id := r.int("held_item_id"); id != 0 {
+			item, err := b.item(id)
+			if err != nil {
+				return nil, err
+			}
+			method.HeldItem = item
+		}
+		return method, nil
+	case "use-item":
+		if err := unsupported(r, "minimum_level", "minimum_happiness", "held_item_id", "known_move_id", "location_id", "time_of_day"); err != nil {
+			return nil, err
+		}
+		item, err := b.item(r.int("trigger_item_id"))
+		if err != nil {
+			return nil, err
+		}
+		if r.int("gender_id") != 0 {
+			return pokemon.GenderEvolution{Gender: gender(r.int("gender_id")), RequiredItem: item}, nil
+		}
+		return pokemon.ItemEvolution{RequiredItem: item}, nil
+	default:
+		return nil, fmt.Errorf("unsupported trigger %q", trigger)
+	}
+}
+
+// levelUpEvolution combines the conditions of a level up evolution, folding the
+// minimum level and time of day into the methods that take them.
+func (b *builder) levelUpEvolution(r row) (pokemon.EvolutionMethod, error) {
+	if err := unsupported(r, "minimum_beauty", "minimum_affection", "known_move_type_id", "party_type_id", "trade_species_id", "turn_upside_down"); err != nil {
+		return nil, err
+	}
+
+	level := r.int("minimum_level")
+	timeOfDay := pokemon.Time(title(r["time_of_day"]))
+	var methods []pokemon.EvolutionMethod
+	levelUsed, timeUsed := level == 0, timeOfDay == ""
+
+	if id := r.int("gender_id"); id != 0 {
+		methods = append(methods, pokemon.GenderEvolution{Gender: gender(id), RequiredLevel: level})
+		levelUsed = true
+	}
+	if r["relative_physical_stats"] != "" {
+		comparison := map[int]pokemon.StatComparison{1: pokemon.AttackHigher, -1: pokemon.DefenseHigher, 0: pokemon.AttackDefenseEqual}
+		methods = append(methods, pokemon.StatComparisonEvolution{RequiredLevel: level, Comparison: comparison[r.int("relative_physical_stats")]})
+		levelUsed = true
+	}
+	if id := r.int("party_species_id"); id != 0 {
+		methods = append(methods, pokemon.PartySpeciesEvolution{SpeciesID: id, RequiredLevel: level})
+		levelUsed = true
+	}
+	if r.int("needs_overworld_rain") == 1 {
+		methods = append(methods, pokemon.WeatherEvolution{Weather: "Rain", RequiredLevel: level})
+		levelUsed = true
+	}
+	if happiness := r.int("minimum_happiness"); happiness != 0 {
+		methods = append(methods, pokemon.FriendshipEvolution{RequiredFriendship: happiness, RequiredTime: timeOfDay})
+		timeUsed = true
+	}
+	if id := r.int("held_item_id"); id != 0 {
+		item, err := b.item(id)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, pokemon.HeldItemEvolution{RequiredItem: item, RequiredTime: timeOfDay})
+		timeUsed = true
+	}
+	if id := r.int("known_move_id"); id != 0 {
+		name, ok := b.moveNames[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown move %d", id)
+		}
+		methods = append(methods, pokemon.KnowsMoveEvolution{MoveName: name})
+	}
+	if id := r.int("location_id"); id != 0 {
+		name, ok := b.locations[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown location %d", id)
+		}
+		methods = append(methods, pokemon.LocationEvolution{Location: name})
+	}
+
+	if !timeUsed {
+		return nil, fmt.Errorf("unsupported time of day %q on its own", r["time_of_day"])
+	}
+	if !levelUsed {
+		methods = append([]pokemon.EvolutionMethod{pokemon.LevelEvolution{RequiredLevel: level}}, methods...)
+	}
+
+	switch len(methods) {
+	case 0:
+		return nil, fmt.Errorf("level up without any condition")
+	case 1:
+		return methods[0], nil
+	default:
+		return pokemon.AndEvolution{Methods: methods}, nil
+	}
+}
+
+func (b *builder) item(id int) (pokemon.Item, error) {
+	identifier, ok := b.items[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown item %d", id)
+	}
+	item, ok := pokemon.ItemByID(pokemon.ItemID(identifier))
+	if !ok {
+		return nil, fmt.Errorf("item %s is not registered", identifier)
+	}
+	return item, nil
+}
+
+// unsupported returns an error if any of the columns hold a condition the method can't express.
+func unsupported(r row, columns ...string) error {
+	for _, column := range columns {
+		if v := r[column]; v != "" && v != "0" {
+			return fmt.Errorf("unsupported condition %s=%s", column, v)
+		}
+	}
+	return nil
+}
+
+func gender(id int) pokemon.Gender {
+	switch id {
+	case femaleGenderID:
+		return pokemon.Female
+	case maleGenderID:
+		return pokemon.Male
+	default:
+		return pokemon.Genderless
+	}
+}
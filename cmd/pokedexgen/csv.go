@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// englishLanguageID is the PokeAPI local_language_id for English names.
+const englishLanguageID = 9
+
+type row map[string]string
+
+// int returns the column as an int, 0 when it is empty.
+func (r row) int(column string) int {
+	n, _ := strconv.Atoi(r[column])
+	return n
+}
+
+// readTable reads a PokeAPI CSV file into rows keyed by the header. Optional tables
+// that don't exist come back empty.
+func readTable(dir, name string, optional bool) ([]row, error) {
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	rows := make([]row, 0, len(records)-1)
+	for _, record := range records[1:] {
+		r := make(row, len(header))
+		for i, column := range header {
+			if i < len(record) {
+				r[column] = record[i]
+			}
+		}
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
+
+// identifiers maps the id column of a table to its identifier column.
+func identifiers(rows []row) map[int]string {
+	m := make(map[int]string, len(rows))
+	for _, r := range rows {
+		m[r.int("id")] = r["identifier"]
+	}
+	return m
+}
+
+// englishNames maps idColumn of a *_names table to the English name.
+func englishNames(rows []row, idColumn string) map[int]string {
+	m := make(map[int]string)
+	for _, r := range rows {
+		if r.int("local_language_id") == englishLanguageID {
+			m[r.int(idColumn)] = r["name"]
+		}
+	}
+	return m
+}
+
+// title turns an identifier such as "thunder-stone" into "Thunder Stone", for
+// when a dump has no names table.
+func title(identifier string) string {
+	words := strings.Split(identifier, "-")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+
+	pokemon "pokemon/pkg"
+)
+
+// PokeAPI move_meta categories whose stat changes go to the user rather than the
+// target. Status moves only go to the user when they target it.
+const (
+	netGoodStatsCategory = 2
+	damageRaiseCategory  = 7
+)
+
+// PokeAPI move effects that the meta tables don't tell apart.
+const (
+	badlyPoisonEffect = 34 // Toxic, whose ailment is plain poison
+	substituteEffect  = 80
+)
+
+var userTargets = map[int]bool{
+	4:  true, // users-field
+	5:  true, // user-or-ally
+	7:  true, // user
+	13: true, // user-and-allies
+}
+
+// moveEffects declares the effects in the optional move_meta tables by move ID.
+// Effects the pkg can't implement are skipped with a warning.
+func (b *builder) moveEffects(moveRows []row) (map[int][]pokemon.EffectSpec, error) {
+	metaRows, err := b.optionalTable("move_meta.csv")
+	if err != nil {
+		return nil, err
+	}
+	ailmentRows, err := b.optionalTable("move_meta_ailments.csv")
+	if err != nil {
+		return nil, err
+	}
+	changeRows, err := b.optionalTable("move_meta_stat_changes.csv")
+	if err != nil {
+		return nil, err
+	}
+	statRows, err := b.optionalTable("stats.csv")
+	if err != nil {
+		return nil, err
+	}
+	ailments, statNames := identifiers(ailmentRows), identifiers(statRows)
+
+	moveTargets := make(map[int]int, len(moveRows))
+	moveEffectIDs := make(map[int]int, len(moveRows))
+	for _, r := range moveRows {
+		moveTargets[r.int("id")] = r.int("target_id")
+		moveEffectIDs[r.int("id")] = r.int("effect_id")
+	}
+
+	changes := make(map[int][]row)
+	for _, r := range changeRows {
+		changes[r.int("move_id")] = append(changes[r.int("move_id")], r)
+	}
+
+	effects := make(map[int][]pokemon.EffectSpec)
+	for _, r := range metaRows {
+		id := r.int("move_id")
+		add := func(kind string, params interface{}) {
+			if spec, ok := b.effectSpec(id, kind, params); ok {
+				effects[id] = append(effects[id], spec)
+			}
+		}
+
+		if r.int("max_hits") > 1 {
+			add("multi_hit", map[string]int{"min": r.int("min_hits"), "max": r.int("max_hits")})
+		}
+		if drain := r.int("drain"); drain > 0 {
+			add("drain", map[string]int{"percent": drain})
+		} else if drain < 0 {
+			add("recoil", map[string]int{"percent": -drain})
+		}
+		if ailment := r.int("meta_ailment_id"); ailment > 0 {
+			status := ailments[ailment]
+			if moveEffectIDs[id] == badlyPoisonEffect {
+				status = "toxic"
+			}
+			add("status", map[string]interface{}{"status": status, "chance": r.int("ailment_chance")})
+		}
+		if flinch := r.int("flinch_chance"); flinch > 0 {
+			add("status", map[string]interface{}{"status": "flinch", "chance": flinch})
+		}
+		if moveEffectIDs[id] == substituteEffect {
+			add("substitute", nil)
+		}
+
+		target := "target"
+		switch r.int("meta_category_id") {
+		case netGoodStatsCategory:
+			if userTargets[moveTargets[id]] {
+				target = "user"
+			}
+		case damageRaiseCategory:
+			target = "user"
+		}
+		moveChanges := changes[id]
+		sort.Slice(moveChanges, func(i, j int) bool { return moveChanges[i].int("stat_id") < moveChanges[j].int("stat_id") })
+		for _, change := range moveChanges {
+			stat, ok := stats[statNames[change.int("stat_id")]]
+			if !ok {
+				b.warnf("move %s: stat change to %s", b.moveNames[id], statNames[change.int("stat_id")])
+				continue
+			}
+			add("stat_change", map[string]interface{}{
+				"stat": stat.String(), "stages": change.int("change"), "target": target, "chance": r.int("stat_chance"),
+			})
+		}
+	}
+	return effects, nil
+}
+
+// effectSpec encodes an effect, whose params may be nil, and checks the pkg can load it.
+func (b *builder) effectSpec(moveID int, kind string, params interface{}) (pokemon.EffectSpec, bool) {
+	var encoded json.RawMessage
+	if params != nil {
+		var err error
+		if encoded, err = json.Marshal(params); err != nil {
+			b.warnf("move %s: %s: %v", b.moveNames[moveID], kind, err)
+			return pokemon.EffectSpec{}, false
+		}
+	}
+
+	spec := pokemon.EffectSpec{Kind: kind, Params: encoded}
+	check := pokemon.Move{Name: b.moveNames[moveID], EffectSpecs: []pokemon.EffectSpec{spec}}
+	if err := check.LoadEffects(); err != nil {
+		b.warnf("%v", err)
+		return pokemon.EffectSpec{}, false
+	}
+	return spec, true
+}
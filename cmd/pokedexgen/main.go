@@ -0,0 +1,67 @@
+// Command pokedexgen rebuilds the embedded species and move data in pkg/data from a
+// local PokeAPI CSV dump (https://github.com/PokeAPI/pokeapi/tree/master/data/v2/csv).
+//
+//	go run ./cmd/pokedexgen -csv path/to/pokeapi/data/v2/csv -out pkg/data -version 2
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	pokemon "pokemon/pkg"
+)
+
+func main() {
+	csvDir := flag.String("csv", "", "directory holding the PokeAPI CSV files")
+	outDir := flag.String("out", "pkg/data", "directory to write pokedex.json and moves.json to")
+	version := flag.String("version", "", "version recorded in the generated data")
+	flag.Parse()
+
+	if *csvDir == "" || *version == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*csvDir, *outDir, *version); err != nil {
+		fmt.Fprintln(os.Stderr, "pokedexgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(csvDir, outDir, version string) error {
+	b := newBuilder(csvDir)
+	moves, err := b.Moves()
+	if err != nil {
+		return err
+	}
+	species, err := b.Species()
+	if err != nil {
+		return err
+	}
+	for _, warning := range b.warnings {
+		fmt.Fprintln(os.Stderr, "skipped:", warning)
+	}
+
+	pokedex := pokemon.Pokedex{Version: version, Species: species}
+	if err := writeJSON(filepath.Join(outDir, "pokedex.json"), pokedex); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(outDir, "moves.json"), pokemon.MoveDex{Version: version, Moves: moves}); err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %d species and %d moves to %s\n", len(species), len(moves), outDir)
+	return nil
+}
+
+// writeJSON writes indented JSON so regenerated data gives readable diffs.
+func writeJSON(filename string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	pokemon "pokemon/pkg"
+)
+
+func buildTestdata(t *testing.T) (*builder, []pokemon.Move, map[int]pokemon.Species) {
+	t.Helper()
+	b := newBuilder("testdata")
+	moves, err := b.Moves()
+	if err != nil {
+		t.Fatalf("Moves() error = %v", err)
+	}
+	species, err := b.Species()
+	if err != nil {
+		t.Fatalf("Species() error = %v", err)
+	}
+
+	byID := make(map[int]pokemon.Species)
+	for _, s := range species {
+		byID[s.ID] = s
+	}
+	return b, moves, byID
+}
+
+func TestMoves(t *testing.T) {
+	_, moves, _ := buildTestdata(t)
+
+	want := map[string]pokemon.Move{
+		"Vine Whip": {ID: 22, Name: "Vine Whip", Type: pokemon.Grass, Category: pokemon.Physical, Power: 45, PP: 25, Accuracy: 100},
+		"Tackle":    {ID: 33, Name: "Tackle", Type: pokemon.Normal, Category: pokemon.Physical, Power: 40, PP: 35, Accuracy: 100},
+		"Swift":     {ID: 129, Name: "Swift", Type: pokemon.Normal, Category: pokemon.Special, Power: 60, PP: 20},
+	}
+	for _, move := range moves {
+		if move.Name == "Swords Dance" && !move.TargetsUser {
+			t.Errorf("Swords Dance should target its user")
+		}
+		if w, ok := want[move.Name]; ok && !reflect.DeepEqual(move, w) {
+			t.Errorf("%s = %+v, want %+v", move.Name, move, w)
+		}
+	}
+
+	for i := 1; i < len(moves); i++ {
+		if moves[i-1].ID >= moves[i].ID {
+			t.Fatalf("Moves() not sorted by ID: %d before %d", moves[i-1].ID, moves[i].ID)
+		}
+	}
+	if len(moves) != 15 {
+		t.Errorf("Moves() returned %d moves, want 15 without shadow-rush", len(moves))
+	}
+}
+
+func TestMoveEffects(t *testing.T) {
+	b, moves, _ := buildTestdata(t)
+	byName := make(map[string]pokemon.Move)
+	for _, move := range moves {
+		byName[move.Name] = move
+	}
+
+	tests := []struct {
+		move string
+		want []string
+	}{
+		{"Growl", []string{`stat_change {"chance":0,"stages":-1,"stat":"Attack","target":"target"}`}},
+		{"Swords Dance", []string{`stat_change {"chance":0,"stages":2,"stat":"Attack","target":"user"}`}},
+		{"Poison Powder", []string{`status {"chance":0,"status":"poison"}`}},
+		{"Toxic", []string{`status {"chance":0,"status":"toxic"}`}},
+		{"Thunder Shock", []string{`status {"chance":10,"status":"paralysis"}`}},
+		{"Leech Life", []string{`drain {"percent":50}`}},
+		{"Take Down", []string{`recoil {"percent":25}`}},
+		{"Fury Attack", []string{`multi_hit {"max":5,"min":2}`}},
+		{"Confusion", []string{`status {"chance":10,"status":"confusion"}`}},
+		{"Headbutt", []string{`status {"chance":30,"status":"flinch"}`}},
+		{"Substitute", []string{`substitute`}},
+		{"Sand Attack", []string{`stat_change {"chance":0,"stages":-1,"stat":"Accuracy","target":"target"}`}},
+		{"Tackle", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.move, func(t *testing.T) {
+			move, ok := byName[tt.move]
+			if !ok {
+				t.Fatalf("%s not generated", tt.move)
+			}
+			var got []string
+			for _, spec := range move.EffectSpecs {
+				got = append(got, strings.TrimSpace(spec.Kind+" "+string(spec.Params)))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EffectSpecs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// every stat, accuracy and evasion included, has stages
+	for _, warning := range b.warnings {
+		if strings.Contains(warning, "stat change") {
+			t.Errorf("Unexpected warning %q", warning)
+		}
+	}
+}
+
+func TestSpecies(t *testing.T) {
+	_, _, species := buildTestdata(t)
+
+	if len(species) != 11 {
+		t.Fatalf("Species() returned %d species, want 11", len(species))
+	}
+
+	bulbasaur := species[1]
+	if bulbasaur.Name != "Bulbasaur" || !reflect.DeepEqual(bulbasaur.Types, []pokemon.Type{pokemon.Grass, pokemon.Poison}) {
+		t.Errorf("Bulbasaur = %s %v, want Bulbasaur [Grass Poison]", bulbasaur.Name, bulbasaur.Types)
+	}
+	if bulbasaur.BaseStats != (pokemon.Stats{HP: 45, Attack: 49, Defense: 49, SpecialAttack: 65, SpecialDefense: 65, Speed: 45}) {
+		t.Errorf("Bulbasaur base stats = %+v", bulbasaur.BaseStats)
+	}
+	if bulbasaur.EVYield != (pokemon.Stats{SpecialAttack: 1}) || bulbasaur.BaseExpYield != 64 || bulbasaur.GrowthRate != pokemon.MediumSlow {
+		t.Errorf("Bulbasaur EV yield %+v, exp yield %d, growth rate %v", bulbasaur.EVYield, bulbasaur.BaseExpYield, bulbasaur.GrowthRate)
+	}
+	if bulbasaur.CaptureRate != 45 {
+		t.Errorf("Bulbasaur capture rate = %d, want 45", bulbasaur.CaptureRate)
+	}
+
+	if got := species[83].Name; got != "Farfetchd" {
+		t.Errorf("species without a name = %q, want the identifier Farfetchd", got)
+	}
+}
+
+func TestLearnsetUsesLatestVersionGroup(t *testing.T) {
+	_, _, species := buildTestdata(t)
+
+	want := pokemon.Learnset{
+		{Level: 1, Move: pokemon.Move{Name: "Tackle"}},
+		{Level: 3, Move: pokemon.Move{Name: "Growl"}},
+		{Level: 9, Move: pokemon.Move{Name: "Vine Whip"}},
+	}
+	if got := species[1].Learnset; !reflect.DeepEqual(got, want) {
+		t.Errorf("Bulbasaur learnset = %+v, want %+v", got, want)
+	}
+}
+
+func TestEvolutions(t *testing.T) {
+	b, _, species := buildTestdata(t)
+
+	tests := []struct {
+		name string
+		from int
+		want []pokemon.EvolutionStage
+	}{
+		{"level", 1, []pokemon.EvolutionStage{pokemon.NewEvolutionStage(2, pokemon.LevelEvolution{RequiredLevel: 16})}},
+		{"trade with held item", 61, []pokemon.EvolutionStage{pokemon.NewEvolutionStage(186, pokemon.TradeEvolution{HeldItem: pokemon.KingsRock})}},
+		{"friendship and location", 133, []pokemon.EvolutionStage{
+			pokemon.NewEvolutionStage(196, pokemon.FriendshipEvolution{RequiredFriendship: 160, RequiredTime: "Day"}),
+			pokemon.NewEvolutionStage(470, pokemon.LocationEvolution{Location: "Eterna Forest"}),
+		}},
+		{"stat comparison", 236, []pokemon.EvolutionStage{pokemon.NewEvolutionStage(106, pokemon.StatComparisonEvolution{RequiredLevel: 20, Comparison: pokemon.AttackHigher})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := species[tt.from].EvolutionStages; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EvolutionStages = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// Sylveon needs affection and a Fairy move, which no evolution method covers
+	var skipped bool
+	for _, warning := range b.warnings {
+		skipped = skipped || strings.Contains(warning, "Sylveon")
+	}
+	if !skipped {
+		t.Errorf("Expected a warning for skipping Sylveon, got %v", b.warnings)
+	}
+}
+
+func TestRun(t *testing.T) {
+	out := t.TempDir()
+	if err := run("testdata", out, "test"); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(out, "pokedex.json"))
+	if err != nil {
+		t.Fatalf("Failed to open generated pokedex: %v", err)
+	}
+	defer f.Close()
+
+	pokedex, err := pokemon.LoadPokedexFromReader(f)
+	if err != nil {
+		t.Fatalf("LoadPokedexFromReader() error = %v", err)
+	}
+	if pokedex.Version != "test" || len(pokedex.Species) != 11 {
+		t.Errorf("Loaded version %q with %d species, want test with 11", pokedex.Version, len(pokedex.Species))
+	}
+	if ivysaur := pokedex.GetSpeciesByID(2); ivysaur == nil || ivysaur.Learnset[0].Move.Power != 40 {
+		t.Errorf("Ivysaur's learnset should resolve to the full Tackle, got %+v", ivysaur)
+	}
+
+	moveDex, err := pokemon.LoadMoveDexFromFS(os.DirFS(out), "moves.json")
+	if err != nil {
+		t.Fatalf("LoadMoveDexFromFS() error = %v", err)
+	}
+	if growl := moveDex.GetMoveByID(45); growl == nil || len(growl.Effects) != 1 {
+		t.Errorf("Growl should load with its stat change, got %+v", growl)
+	}
+}
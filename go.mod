@@ -3,3 +3,5 @@ module pokemon
 go 1.22.1
 
 require github.com/dghubble/trie v0.1.0
+
+require gopkg.in/yaml.v3 v3.0.1
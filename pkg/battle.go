@@ -1,6 +1,6 @@
 package pokemon
 
-//import "fmt"
+import "fmt"
 
 type ActionType int
 
@@ -9,41 +9,47 @@ const (
 	UseItem
 	SwitchPokemon
 	Flee
+	Forfeit
 )
 
 type BattleAction struct {
-	Type     ActionType
+	Type ActionType
+	// MoveSlot is the attacking Pokemon's move to use, Battle fills in Move from it
+	// and spends its PP. Move is Struggle when there is no PP left. For an item like
+	// an Ether it's the move of the Target Pokemon the item goes to.
+	MoveSlot int
 	Move     Move
 	Item     Item
+	Target   int // team slot Item is used on
 	SwitchTo int
 }
 
-//type BattlerAction struct {
-//	Action  BattleAction
-//	Battler Battler
-//}
+//	type BattlerAction struct {
+//		Action  BattleAction
+//		Battler Battler
+//	}
+
+// Battler is a side of a battle. It only decides what to do, Battle carries the action
+// out by the same rules for everyone.
 type Battler interface {
 	ChooseAction() BattleAction
-	ExecuteAction(action BattleAction, opponent *Pokemon)
 	GetPokemon() *Pokemon
 }
 
+// MoveLearner is implemented by battlers that want to decide which move to forget
+// when one of their Pokemon levels up with a full moveset. Otherwise new moves are skipped.
+type MoveLearner interface {
+	DecideMoveLearn(req MoveLearnRequest) (slot int, learn bool)
+}
+
 type WildPokemon struct {
 	Pokemon *Pokemon
 }
 
 func (w *WildPokemon) ChooseAction() BattleAction {
-	//We can add more logic on these later but just jam first move always for tests
-	return BattleAction{Type: Attack, Move: w.Pokemon.Moves[0]}
-}
-
-func (w *WildPokemon) ExecuteAction(action BattleAction, opponent *Pokemon) {
-	//doing minimal logic for now...
-	if action.Type == Attack {
-        action.Move.Execute(w.GetPokemon(), opponent)
-		//fmt.Printf("Wild %s used %s!\n", w.Pokemon.Species.Name, action.Move.Name)
-		//opponent.Health.Current -= action.Move.Power // will add damage calculating at a later time
-	}
+	//We can add more logic on these later but just jam first usable move always for tests
+	slot, _ := w.Pokemon.FirstUsableMove()
+	return BattleAction{Type: Attack, MoveSlot: slot}
 }
 
 func (w *WildPokemon) GetPokemon() *Pokemon {
@@ -52,7 +58,8 @@ func (w *WildPokemon) GetPokemon() *Pokemon {
 
 func (t *Trainer) ChooseAction() BattleAction {
 	// we need logic here somehow to choose an action...
-	return BattleAction{Type: Attack, Move: t.Team[0].Moves[0]}
+	slot, _ := t.Team[0].FirstUsableMove()
+	return BattleAction{Type: Attack, MoveSlot: slot}
 }
 
 func (t *Trainer) GetPokemon() *Pokemon {
@@ -62,65 +69,305 @@ func (t *Trainer) GetPokemon() *Pokemon {
 	return nil
 }
 
-func (t *Trainer) ExecuteAction(action BattleAction, opponent *Pokemon) {
-	switch action.Type {
-	case Attack:
-		action.Move.Execute(t.GetPokemon(), opponent)
-		opponent.Health.decrease(10)
-	case UseItem:
-		// later
-	case SwitchPokemon:
-		// later
-	case Flee:
-		// later
-	}
+// EndReason is why a battle ended.
+type EndReason int
+
+const (
+	AllFainted EndReason = iota // one side has no Pokemon left that can fight
+	Fled
+	Captured
+	Forfeited
+	TurnLimit
+)
+
+func (r EndReason) String() string {
+	return [...]string{"AllFainted", "Fled", "Captured", "Forfeited", "TurnLimit"}[r]
 }
 
+// BattleResult is how a battle ended. Winner is nil when nobody won, after fleeing,
+// hitting the turn limit or both sides fainting at once.
+type BattleResult struct {
+	Winner Battler
+	Reason EndReason
+	Turns  int
+}
+
+// DefaultMaxTurns is the turn limit NewBattle sets.
+const DefaultMaxTurns = 1000
+
 type Battle struct {
 	Battler1 Battler
 	Battler2 Battler
 	Running  bool
+	Log      BattleLog
+	// MaxTurns calls the battle off after that many turns, 0 means no limit.
+	MaxTurns int
+	Result   BattleResult
+	turn     int
+	// fleeAttempts counts each battler's tries at running away, every one makes the
+	// next likelier to work
+	fleeAttempts map[Battler]int
+
+	// Evolutions, if set, is consulted for every Pokemon that levelled up once the battle is over.
+	Evolutions      *EvolutionEngine
+	Environment     EvolutionContext // time, weather and location the battle takes place in
+	EvolutionEvents []EvolutionEvent
+
+	// participants holds every Pokemon that has been out against a given Pokemon
+	participants map[*Pokemon][]*Pokemon
+	levelledUp   []*Pokemon
 }
 
 func NewBattle(battler1, battler2 Battler) *Battle {
 	return &Battle{
-		Battler1: battler1,
-		Battler2: battler2,
-		Running:  true,
+		Battler1:     battler1,
+		Battler2:     battler2,
+		Running:      true,
+		MaxTurns:     DefaultMaxTurns,
+		participants: make(map[*Pokemon][]*Pokemon),
+		fleeAttempts: make(map[Battler]int),
 	}
 }
 
-func (b *Battle) Run() {
+// Run plays turns until one side has no Pokemon left, someone flees, forfeits or
+// catches the other's Pokemon, or the turn limit is reached.
+func (b *Battle) Run() BattleResult {
 	for b.Running {
-		action1 := b.Battler1.ChooseAction()
-		action2 := b.Battler2.ChooseAction()
+		b.turn++
+		b.recordParticipants()
+		b.startTurn(b.Battler1)
+		b.startTurn(b.Battler2)
+
+		action1 := b.prepareAction(b.Battler1, b.Battler1.ChooseAction())
+		action2 := b.prepareAction(b.Battler2, b.Battler2.ChooseAction())
 
 		// Determine the order of execution based on priority and speed
 		firstBattler, secondBattler := b.determineActionOrder(b.Battler1, action1, b.Battler2, action2)
 
+		firstAction, secondAction := action1, action2
+		if firstBattler != b.Battler1 {
+			firstAction, secondAction = action2, action1
+		}
+
 		// Execute actions in the determined order
-		firstBattler.ExecuteAction(action1, secondBattler.GetPokemon())
-		if secondBattler.GetPokemon().Health.Current > 0 {
-			secondBattler.ExecuteAction(action2, firstBattler.GetPokemon())
+		b.takeAction(firstBattler, firstAction, secondBattler)
+		if !b.Running {
+			break
+		}
+		b.takeAction(secondBattler, secondAction, firstBattler)
+		if !b.Running {
+			break
+		}
+
+		b.endTurn(firstBattler, secondBattler)
+		b.endTurn(secondBattler, firstBattler)
+
+		b.replaceFainted(b.Battler1)
+		b.replaceFainted(b.Battler2)
+		b.checkEndConditions()
+	}
+
+	b.leaveField()
+	b.checkEvolutions()
+	return b.Result
+}
+
+// end stops the battle after the current action.
+func (b *Battle) end(winner Battler, reason EndReason) {
+	b.Running = false
+	b.Result = BattleResult{Winner: winner, Reason: reason, Turns: b.turn}
+}
+
+// leaveField ends what only lasts for the battle, stat stages and volatile statuses, on
+// the Pokemon still out.
+func (b *Battle) leaveField() {
+	for _, battler := range []Battler{b.Battler1, b.Battler2} {
+		if pokemon := battler.GetPokemon(); pokemon != nil {
+			pokemon.SwitchOut()
+		}
+	}
+}
+
+// prepareAction fills in the move an attack uses. A slot without PP falls back to the
+// first move that has some, and a Pokemon with no PP at all uses Struggle.
+func (b *Battle) prepareAction(battler Battler, action BattleAction) BattleAction {
+	if action.Type != Attack {
+		return action
+	}
+
+	pokemon := battler.GetPokemon()
+	pokemon.StatusManager.OverrideAction(pokemon, &action)
+	if action.MoveSlot < 0 || action.MoveSlot >= len(pokemon.Moves) || !pokemon.Moves[action.MoveSlot].CanUse() {
+		slot, ok := pokemon.FirstUsableMove()
+		if !ok {
+			action.MoveSlot = -1
+			action.Move = Struggle
+			return action
+		}
+		action.MoveSlot = slot
+	}
+	action.Move = pokemon.Moves[action.MoveSlot].Move
+	return action
+}
+
+// takeAction runs battler's action, then logs and rewards every Pokemon it knocked out:
+// the target, or the user itself through recoil or hurting itself in confusion.
+func (b *Battle) takeAction(battler Battler, action BattleAction, opponent Battler) {
+	user, target := battler.GetPokemon(), opponent.GetPokemon()
+	userStanding, targetStanding := !user.Health.IsFainted(), !target.Health.IsFainted()
+
+	b.act(battler, action, opponent)
+
+	if targetStanding && target.Health.IsFainted() {
+		b.logFainted(target)
+		b.rewardVictors(battler, opponent)
+	}
+	if userStanding && user.Health.IsFainted() {
+		b.logFainted(user)
+		b.rewardVictors(opponent, battler)
+	}
+}
+
+// act carries out battler's action unless its statuses stop it from moving.
+func (b *Battle) act(battler Battler, action BattleAction, opponent Battler) {
+	pokemon := battler.GetPokemon()
+	if pokemon.Health.IsFainted() {
+		return
+	}
+
+	switch action.Type {
+	case Flee:
+		b.flee(battler, opponent)
+		return
+	case Forfeit:
+		b.end(opponent, Forfeited)
+		return
+	case SwitchPokemon:
+		b.switchPokemon(battler, action.SwitchTo)
+		return
+	case UseItem:
+		b.useItem(battler, action, opponent)
+		return
+	case Attack:
+		if !pokemon.StatusManager.BeforeAction(pokemon, &action, &b.Log) {
+			return
+		}
+		b.Log.Add("%s used %s!", pokemon.Name(), action.Move.Name)
+		if action.MoveSlot >= 0 {
+			pokemon.lastMove = action.Move.Name
 		}
+	}
+	b.spendPP(battler, action)
+	action.Move.Execute(pokemon, opponent.GetPokemon())
+}
+
+// startTurn lets the statuses of a Pokemon still standing act before anyone moves.
+func (b *Battle) startTurn(battler Battler) {
+	if pokemon := battler.GetPokemon(); !pokemon.Health.IsFainted() {
+		pokemon.StatusManager.TurnStart(pokemon)
+	}
+}
+
+// endTurn runs the end of turn statuses, such as burn and poison damage.
+func (b *Battle) endTurn(battler, opponent Battler) {
+	pokemon := battler.GetPokemon()
+	if pokemon.Health.IsFainted() {
+		return
+	}
+	// Leech Seed heals whoever is on the other side now, not only the Pokemon that used it
+	pokemon.seedRecipient(opponent.GetPokemon())
+	pokemon.StatusManager.TurnEnd(pokemon, &b.Log)
+	if pokemon.Health.IsFainted() {
+		b.logFainted(pokemon)
+		b.rewardVictors(opponent, battler)
+	}
+}
+
+func (b *Battle) logFainted(pokemon *Pokemon) {
+	if pokemon.Health.IsFainted() {
+		b.Log.Add("%s fainted!", pokemon.Name())
+	}
+}
 
-		// Check for end conditions
-		if b.checkEndConditions() {
-			b.Running = false
+// spendPP uses up a PP of the attack's move once it's the Pokemon's turn to act.
+func (b *Battle) spendPP(battler Battler, action BattleAction) {
+	if action.Type == Attack && action.MoveSlot >= 0 {
+		battler.GetPokemon().Moves[action.MoveSlot].UsePP()
+	}
+}
+
+// checkEvolutions runs once the battle is over, Pokemon don't evolve mid-battle.
+func (b *Battle) checkEvolutions() {
+	if b.Evolutions == nil {
+		return
+	}
+	for _, p := range b.levelledUp {
+		if p.Health.IsFainted() {
+			continue
+		}
+		ctx := b.Environment
+		ctx.Party = b.party(p)
+		event, err := b.Evolutions.AfterLevelUp(p, ctx)
+		if err != nil {
+			b.Log.Add("%s couldn't evolve: %v", p.Name(), err)
+			continue
 		}
+		if event != nil {
+			b.EvolutionEvents = append(b.EvolutionEvents, *event)
+		}
+	}
+}
+
+// Every action other than an attack goes before any move, which have priorities up to
+// +5: fleeing first, then switching, then items.
+const (
+	itemPriority = 6 + iota
+	switchPriority
+	fleePriority
+)
+
+func actionPriority(action BattleAction) int {
+	switch action.Type {
+	case Flee, Forfeit:
+		return fleePriority
+	case SwitchPokemon:
+		return switchPriority
+	case UseItem:
+		return itemPriority
 	}
+	return action.Move.Priority
+}
+
+// party is the team of the battler that p belongs to, nil if it isn't on a team.
+func (b *Battle) party(p *Pokemon) []*Pokemon {
+	for _, battler := range []Battler{b.Battler1, b.Battler2} {
+		team, ok := battler.(TeamBattler)
+		if !ok {
+			continue
+		}
+		party := make([]*Pokemon, TeamSize)
+		onTeam := false
+		for slot := range party {
+			party[slot] = team.PokemonAt(slot)
+			onTeam = onTeam || party[slot] == p
+		}
+		if onTeam {
+			return party
+		}
+	}
+	return nil
 }
 
 // determineActionOrder determines which battler should act first based on action priority and speed
 func (b *Battle) determineActionOrder(battler1 Battler, action1 BattleAction, battler2 Battler, action2 BattleAction) (firstBattler, secondBattler Battler) {
-	switch {
-	case action1.Move.Priority > action2.Move.Priority:
+	switch priority1, priority2 := actionPriority(action1), actionPriority(action2); {
+	case priority1 > priority2:
 		return battler1, battler2
-	case action1.Move.Priority < action2.Move.Priority:
+	case priority1 < priority2:
 		return battler2, battler1
-	case b.Battler1.GetPokemon().Stats.Speed > b.Battler2.GetPokemon().Stats.Speed:
+	case b.Battler1.GetPokemon().effectiveSpeed() > b.Battler2.GetPokemon().effectiveSpeed():
 		return battler1, battler2
-	case b.Battler1.GetPokemon().Stats.Speed < b.Battler2.GetPokemon().Stats.Speed:
+	case b.Battler1.GetPokemon().effectiveSpeed() < b.Battler2.GetPokemon().effectiveSpeed():
 		return battler2, battler1
 	default:
 		// If priority and speed are the same, choose randomly or by another logic
@@ -129,6 +376,272 @@ func (b *Battle) determineActionOrder(battler1 Battler, action1 BattleAction, ba
 	}
 }
 
-func (b *Battle) checkEndConditions() bool {
-	return true
+func (b *Battle) recordParticipants() {
+	p1, p2 := b.Battler1.GetPokemon(), b.Battler2.GetPokemon()
+	b.addParticipant(p1, p2)
+	b.addParticipant(p2, p1)
+}
+
+func (b *Battle) addParticipant(against, participant *Pokemon) {
+	for _, p := range b.participants[against] {
+		if p == participant {
+			return
+		}
+	}
+	b.participants[against] = append(b.participants[against], participant)
+}
+
+// rewardVictors hands out effort values and experience to everything that fought
+// loser's fainted Pokemon, letting victor pick moves to learn on level up.
+func (b *Battle) rewardVictors(victor, loser Battler) {
+	fainted := loser.GetPokemon()
+	if fainted.Species == nil {
+		return
+	}
+
+	for _, p := range b.participants[fainted] {
+		if !p.Health.IsFainted() {
+			p.AddEVs(fainted.Species.EVYield)
+		}
+	}
+
+	_, wild := loser.(*WildPokemon)
+	gains := AwardExperience(fainted, b.participants[fainted], !wild)
+
+	learner, ok := victor.(MoveLearner)
+	for p, gain := range gains {
+		if len(gain.LevelUps) > 0 {
+			b.addLevelledUp(p)
+		}
+		if !ok {
+			continue
+		}
+		for _, levelUp := range gain.LevelUps {
+			for _, req := range levelUp.PendingMoves {
+				if err := req.Resolve(learner.DecideMoveLearn); err != nil {
+					b.Log.Add("%s couldn't learn %s: %v", p.Name(), req.Move.Name, err)
+				}
+			}
+		}
+	}
+}
+
+func (b *Battle) addLevelledUp(pokemon *Pokemon) {
+	for _, p := range b.levelledUp {
+		if p == pokemon {
+			return
+		}
+	}
+	b.levelledUp = append(b.levelledUp, pokemon)
+}
+
+// BattleLog records what happened during a battle as messages, in order.
+type BattleLog struct {
+	Messages []string
+}
+
+// Add records a message, doing nothing on a nil log.
+func (l *BattleLog) Add(format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+	l.Messages = append(l.Messages, fmt.Sprintf(format, args...))
+}
+
+// checkEndConditions ends the battle once a side is out of Pokemon or the turn limit
+// is reached.
+func (b *Battle) checkEndConditions() {
+	lost1, lost2 := defeated(b.Battler1), defeated(b.Battler2)
+	switch {
+	case lost1 && lost2:
+		b.end(nil, AllFainted)
+	case lost1:
+		b.end(b.Battler2, AllFainted)
+	case lost2:
+		b.end(b.Battler1, AllFainted)
+	case b.MaxTurns > 0 && b.turn >= b.MaxTurns:
+		b.end(nil, TurnLimit)
+	}
+}
+
+// TeamBattler is a battler with more Pokemon than the one it has out. When that one
+// faints the battle asks ChooseReplacement for the team slot to send out next.
+type TeamBattler interface {
+	Battler
+	HasUsablePokemon() bool
+	ChooseReplacement() int
+	PokemonAt(slot int) *Pokemon
+	SwapActivePokemon(slot int) bool
+}
+
+// defeated reports whether battler has no Pokemon left that can fight.
+func defeated(battler Battler) bool {
+	if team, ok := battler.(TeamBattler); ok {
+		return !team.HasUsablePokemon()
+	}
+	pokemon := battler.GetPokemon()
+	return pokemon == nil || pokemon.Health.IsFainted()
+}
+
+// throwBall tries to catch the opponent's Pokemon, which only works on wild ones. A
+// trainer keeps what it catches.
+func (b *Battle) throwBall(battler Battler, ball CaptureItem, opponent Battler) {
+	target := opponent.GetPokemon()
+	if _, wild := opponent.(*WildPokemon); !wild {
+		b.Log.Add("The trainer blocked the %s!", ball.Name())
+		return
+	}
+	if !ball.Catch(target) {
+		b.Log.Add("Oh no! %s broke free!", target.Name())
+		return
+	}
+
+	b.Log.Add("Gotcha! %s was caught!", target.Name())
+	if trainer, ok := battler.(*Trainer); ok {
+		trainer.AddPokemon(target)
+	}
+	b.end(battler, Captured)
+}
+
+// replaceFainted sends out the next Pokemon of a team battler whose active one has
+// fainted. Nothing is sent out when the whole team has fainted, which ends the battle.
+func (b *Battle) replaceFainted(battler Battler) {
+	team, ok := battler.(TeamBattler)
+	if !ok || !team.GetPokemon().Health.IsFainted() {
+		return
+	}
+	slot := replacementSlot(team)
+	if slot < 0 {
+		return
+	}
+
+	b.sendOut(team, slot)
+}
+
+// sendOut takes the active Pokemon off the field and puts the one in slot in its place,
+// ending the statuses it kept up on the opponent.
+func (b *Battle) sendOut(team TeamBattler, slot int) {
+	outgoing := team.GetPokemon()
+	outgoing.SwitchOut()
+	if opponent := b.opponent(team).GetPokemon(); opponent != nil {
+		opponent.StatusManager.SourceLeft(outgoing)
+	}
+	team.SwapActivePokemon(slot)
+	b.Log.Add("Go! %s!", team.GetPokemon().Name())
+}
+
+// opponent is the other side of the battle from battler.
+func (b *Battle) opponent(battler Battler) Battler {
+	if battler == b.Battler1 {
+		return b.Battler2
+	}
+	return b.Battler1
+}
+
+// replacementSlot is the slot the battler chose if it holds a healthy Pokemon other
+// than the active one, otherwise the first slot that does, or -1 if none do.
+func replacementSlot(team TeamBattler) int {
+	if slot := team.ChooseReplacement(); canSendOut(team, slot) {
+		return slot
+	}
+	for slot := 1; slot < TeamSize; slot++ {
+		if canSendOut(team, slot) {
+			return slot
+		}
+	}
+	return -1
+}
+
+func canSendOut(team TeamBattler, slot int) bool {
+	pokemon := team.PokemonAt(slot)
+	return slot > 0 && pokemon != nil && !pokemon.Health.IsFainted()
+}
+
+// switchPokemon swaps the battler's active Pokemon for the healthy one in slot, unless
+// it's trapped.
+func (b *Battle) switchPokemon(battler Battler, slot int) {
+	pokemon := battler.GetPokemon()
+	team, ok := battler.(TeamBattler)
+	switch {
+	case !ok || !canSendOut(team, slot):
+		b.Log.Add("%s can't switch with that Pokemon!", pokemon.Name())
+	case pokemon.Trapped():
+		b.Log.Add("%s can't be switched out!", pokemon.Name())
+	default:
+		b.Log.Add("Come back, %s!", pokemon.Name())
+		b.sendOut(team, slot)
+		b.recordParticipants()
+	}
+}
+
+// ItemBattler is a team battler with a bag, which the items it uses in battle come out of.
+type ItemBattler interface {
+	TeamBattler
+	UseItemOn(slot, moveSlot int, item Item) error
+	RemoveItem(item Item) bool
+}
+
+// useItem throws a ball at the opponent or uses an item on a Pokemon of the battler's
+// team. A battler without a bag can only use items on its active Pokemon.
+func (b *Battle) useItem(battler Battler, action BattleAction, opponent Battler) {
+	if action.Item == nil {
+		return
+	}
+	bag, hasBag := battler.(ItemBattler)
+	if ball, ok := action.Item.(CaptureItem); ok {
+		if hasBag && !bag.RemoveItem(ball) {
+			b.Log.Add("There are no %ss left!", ball.Name())
+			return
+		}
+		b.throwBall(battler, ball, opponent)
+		return
+	}
+
+	target := battler.GetPokemon()
+	var err error
+	if hasBag {
+		target = bag.PokemonAt(action.Target)
+		err = bag.UseItemOn(action.Target, action.MoveSlot, action.Item)
+	} else {
+		err = target.ApplyItem(action.Item, action.MoveSlot)
+	}
+	if err != nil {
+		b.Log.Add("%v", err)
+		return
+	}
+	b.Log.Add("Used the %s on %s!", action.Item.Name(), target.Name())
+}
+
+// flee runs from a wild battle. It always works for a Pokemon at least as fast as the
+// opponent's, otherwise the chance out of 256 is speed*128/opponent's speed, plus 30
+// for every attempt so far including this one. Trapped Pokemon can't flee.
+func (b *Battle) flee(battler, opponent Battler) {
+	pokemon := battler.GetPokemon()
+	if b.trainerBattle() {
+		b.Log.Add("No! There's no running from a trainer battle!")
+		return
+	}
+	if pokemon.Trapped() {
+		b.Log.Add("%s can't escape!", pokemon.Name())
+		return
+	}
+
+	b.fleeAttempts[battler]++
+	speed, opponentSpeed := pokemon.effectiveSpeed(), opponent.GetPokemon().effectiveSpeed()
+	if speed < opponentSpeed {
+		chance := speed*128/opponentSpeed + 30*b.fleeAttempts[battler]
+		if chance <= 255 && rng.Intn(256) >= chance {
+			b.Log.Add("Can't escape!")
+			return
+		}
+	}
+	b.Log.Add("Got away safely!")
+	b.end(nil, Fled)
+}
+
+// trainerBattle reports whether neither side is a wild Pokemon.
+func (b *Battle) trainerBattle() bool {
+	_, wild1 := b.Battler1.(*WildPokemon)
+	_, wild2 := b.Battler2.(*WildPokemon)
+	return !wild1 && !wild2
 }
@@ -2,6 +2,8 @@ package pokemon
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -15,43 +17,60 @@ func (mb *MockBattler) ChooseAction() BattleAction {
 	return mb.Action
 }
 
-func (mb *MockBattler) ExecuteAction(action BattleAction, opponent *Pokemon) {
+func (mb *MockBattler) GetPokemon() *Pokemon {
+	return mb.Pokemon
+}
 
-	if action.Type == Attack {
-        fmt.Println("hello world im here")
-        action.Move.Execute(mb.GetPokemon(), opponent)
-	// Simplified logic for testing: just reduce the opponent's health by the move's power
-    }
+// LearningBattler always forgets its first move for a new one.
+type LearningBattler struct {
+	MockBattler
 }
 
-func (mb *MockBattler) GetPokemon() *Pokemon {
-	return mb.Pokemon
+func (lb *LearningBattler) DecideMoveLearn(req MoveLearnRequest) (int, bool) {
+	return 0, true
+}
+
+// moveOrder lists the Pokemon that used a move, in the order the log has them.
+func moveOrder(log BattleLog) []string {
+	var order []string
+	for _, message := range log.Messages {
+		if name, _, ok := strings.Cut(message, " used "); ok {
+			order = append(order, name)
+		}
+	}
+	return order
 }
 
 // TestBattleOrder tests the order of actions in a battle based on move priority and speed
 func TestBattleOrder(t *testing.T) {
+	setRandomizer(t, highRolls)
+
 	// Setup mock Pokémon with different speeds and moves
-	quickAttack := Move{Name: "Quick Attack", Power: 40, Priority: 1}
-	tackle := Move{Name: "Tackle", Power: 50, Priority: 0}
+	quickAttack := Move{Name: "Quick Attack", Category: Physical, Power: 40, PP: 30, Accuracy: 100, Priority: 1}
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 50, PP: 35, Accuracy: 100, Priority: 0}
 
-	fastPokemon := &Pokemon{Species: CharmanderSpecies, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 90}, Moves: [4]Move{quickAttack}}
-	slowPokemon := &Pokemon{Species: BulbasaurSpecies, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 45}, Moves: [4]Move{tackle}}
+	fastPokemon := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 90}, Moves: NewMoveSlots([4]Move{quickAttack})}
+	slowPokemon := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 45}, Moves: NewMoveSlots([4]Move{tackle})}
 
 	fastBattler := &MockBattler{Pokemon: fastPokemon, Action: BattleAction{Type: Attack, Move: quickAttack}}
 	slowBattler := &MockBattler{Pokemon: slowPokemon, Action: BattleAction{Type: Attack, Move: tackle}}
 
-	// Run the battle
-	battle := NewBattle(fastBattler, slowBattler)
+	// Run the battle with the slow battler listed first so ordering has to come from priority
+	battle := NewBattle(slowBattler, fastBattler)
+	battle.MaxTurns = 1
 	battle.Run()
 
 	// Verify that the fast Pokémon with the priority move attacked first
-	if slowPokemon.Health.Current != 60 {
-		t.Errorf("Expected slow Pokémon to have 60 health after Quick Attack, got %d", slowPokemon.Health.Current)
+	if order := moveOrder(battle.Log); len(order) != 2 || order[0] != "Charmander" || order[1] != "Bulbasaur" {
+		t.Errorf("Expected Charmander to act before Bulbasaur, got %v", order)
 	}
 
-	// Verify that the slow Pokémon attacked second
-	if fastPokemon.Health.Current != 50 {
-		t.Errorf("Expected fast Pokémon to have 50 health after Tackle, got %d", fastPokemon.Health.Current)
+	// Damage comes from the damage formula: 40 power does 6, 50 power does 8 at these stats
+	if slowPokemon.Health.Current != 94 {
+		t.Errorf("Expected slow Pokémon to have 94 health after Quick Attack, got %d", slowPokemon.Health.Current)
+	}
+	if fastPokemon.Health.Current != 92 {
+		t.Errorf("Expected fast Pokémon to have 92 health after Tackle, got %d", fastPokemon.Health.Current)
 	}
 }
 
@@ -59,8 +78,8 @@ func TestStatusEffects(t *testing.T) {
 	poisonSting := newPoisonMove()
 	sleepingPowder := NewSleepMove()
 
-	sleepPokemon := &Pokemon{Species: CharmanderSpecies, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 90}, Moves: [4]Move{sleepingPowder}}
-	poisonPokemon := &Pokemon{Species: BulbasaurSpecies, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 50}, Moves: [4]Move{poisonSting}}
+	sleepPokemon := &Pokemon{Species: CharmanderSpecies, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 90}, Moves: NewMoveSlots([4]Move{sleepingPowder})}
+	poisonPokemon := &Pokemon{Species: BulbasaurSpecies, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 50}, Moves: NewMoveSlots([4]Move{poisonSting})}
 	sleepBattler := &MockBattler{Pokemon: sleepPokemon, Action: BattleAction{Type: Attack, Move: sleepingPowder}}
 	poisonBattler := &MockBattler{Pokemon: poisonPokemon, Action: BattleAction{Type: Attack, Move: poisonSting}}
 	battle := NewBattle(sleepBattler, poisonBattler)
@@ -69,3 +88,621 @@ func TestStatusEffects(t *testing.T) {
 	fmt.Printf("%v, %v", battle.Battler1.GetPokemon().Health, battle.Battler2.GetPokemon().Health)
 
 }
+
+func TestBattleAwardsEVs(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 50, PP: 35, Accuracy: 100}
+	winnerSpecies := &Species{Name: "Machop", BaseStats: Stats{HP: 70, Attack: 80, Defense: 50, SpecialAttack: 35, SpecialDefense: 35, Speed: 35}}
+	loserSpecies := &Species{Name: "Caterpie", EVYield: Stats{HP: 1}, BaseExpYield: 39}
+
+	winner := NewPokemon(winnerSpecies, 50, nil, nil, [4]Move{tackle})
+	loser := &Pokemon{Species: loserSpecies, Level: 2, Health: Health{Current: 1, Max: 1}, Stats: Stats{Defense: 5, Speed: 100}}
+
+	battle := NewBattle(&MockBattler{Pokemon: winner, Action: BattleAction{Type: Attack, Move: tackle}}, &MockBattler{Pokemon: loser, Action: BattleAction{Type: Attack, Move: tackle}})
+	battle.Run()
+
+	if !loser.Health.IsFainted() {
+		t.Fatalf("Expected Caterpie to faint")
+	}
+	if winner.EVs().HP != 1 {
+		t.Errorf("Expected Machop to gain 1 HP EV, got %v", winner.EVs())
+	}
+	// 39 * 2 / 7 = 11, boosted by half again since MockBattler isn't a wild Pokemon
+	if winner.Experience != 125000+16 {
+		t.Errorf("Expected Machop to gain 16 experience, got %d", winner.Experience-125000)
+	}
+}
+
+func TestWildBattleExperience(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 50, PP: 35, Accuracy: 100}
+	species := &Species{Name: "Machop", BaseStats: Stats{HP: 70, Attack: 80, Defense: 50, SpecialAttack: 35, SpecialDefense: 35, Speed: 35}}
+	winner := NewPokemon(species, 50, nil, nil, [4]Move{tackle})
+	wild := &Pokemon{Species: &Species{Name: "Caterpie", BaseExpYield: 39}, Level: 2, Health: Health{Current: 1, Max: 1}, Moves: NewMoveSlots([4]Move{tackle})}
+
+	battle := NewBattle(&MockBattler{Pokemon: winner, Action: BattleAction{Type: Attack, Move: tackle}}, &WildPokemon{Pokemon: wild})
+	battle.Run()
+
+	if winner.Experience != 125000+11 {
+		t.Errorf("Expected Machop to gain 11 experience, got %d", winner.Experience-125000)
+	}
+}
+
+func TestBattleLevelUpLearnsMoves(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 50, PP: 35, Accuracy: 100}
+	species := &Species{
+		Name:      "Machop",
+		BaseStats: Stats{HP: 70, Attack: 80, Defense: 50, SpecialAttack: 35, SpecialDefense: 35, Speed: 35},
+		Learnset:  Learnset{{Level: 6, Move: Move{Name: "Karate Chop"}}},
+	}
+	moves := [4]Move{tackle, {Name: "Leer"}, {Name: "Focus Energy"}, {Name: "Low Kick"}}
+	winner := NewPokemon(species, 5, nil, nil, moves)
+	wild := &Pokemon{Species: &Species{Name: "Caterpie", BaseExpYield: 200}, Level: 5, Health: Health{Current: 1, Max: 1}, Moves: NewMoveSlots([4]Move{tackle})}
+
+	battle := NewBattle(&LearningBattler{MockBattler{Pokemon: winner, Action: BattleAction{Type: Attack, Move: tackle}}}, &WildPokemon{Pokemon: wild})
+	battle.Run()
+
+	if winner.Level < 6 {
+		t.Fatalf("Expected Machop to reach level 6, got %d", winner.Level)
+	}
+	if winner.Moves[0].Move.Name != "Karate Chop" {
+		t.Errorf("Expected Karate Chop to replace Tackle, got %v", winner.Moves[0].Move.Name)
+	}
+}
+
+// BadLearner asks for a move slot that doesn't exist.
+type BadLearner struct {
+	MockBattler
+}
+
+func (bl *BadLearner) DecideMoveLearn(req MoveLearnRequest) (int, bool) {
+	return 9, true
+}
+
+func TestBattleLogsFailedMoveLearn(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 50, PP: 35, Accuracy: 100}
+	species := &Species{
+		Name:      "Machop",
+		BaseStats: Stats{HP: 70, Attack: 80, Defense: 50, SpecialAttack: 35, SpecialDefense: 35, Speed: 35},
+		Learnset:  Learnset{{Level: 6, Move: Move{Name: "Karate Chop"}}},
+	}
+	moves := [4]Move{tackle, {Name: "Leer"}, {Name: "Focus Energy"}, {Name: "Low Kick"}}
+	winner := NewPokemon(species, 5, nil, nil, moves)
+	wild := &Pokemon{Species: &Species{Name: "Caterpie", BaseExpYield: 200}, Level: 5, Health: Health{Current: 1, Max: 1}, Moves: NewMoveSlots([4]Move{tackle})}
+
+	battle := NewBattle(&BadLearner{MockBattler{Pokemon: winner, Action: BattleAction{Type: Attack}}}, &WildPokemon{Pokemon: wild})
+	battle.Run()
+
+	want := "Machop couldn't learn Karate Chop: invalid move slot 9"
+	for _, message := range battle.Log.Messages {
+		if message == want {
+			return
+		}
+	}
+	t.Errorf("log = %q, want %q", battle.Log.Messages, want)
+}
+
+func TestBattleSpendsPP(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	ember := Move{Name: "Ember", Type: Fire, Category: Special, Power: 40, PP: 25, Accuracy: 100}
+	attacker := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 90}, Moves: NewMoveSlots([4]Move{tackle, ember})}
+	defender := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 45}, Moves: NewMoveSlots([4]Move{tackle})}
+	attacker.Moves[0].PP = 0
+
+	battle := NewBattle(&MockBattler{Pokemon: attacker, Action: BattleAction{Type: Attack, MoveSlot: 0}}, &MockBattler{Pokemon: defender, Action: BattleAction{Type: Attack}})
+	battle.MaxTurns = 1
+	battle.Run()
+
+	if attacker.Moves[1].PP != 24 {
+		t.Errorf("Expected the empty Tackle to fall back to Ember with 24 PP left, got %d", attacker.Moves[1].PP)
+	}
+	if defender.Moves[0].PP != 34 {
+		t.Errorf("Expected the defender's Tackle to have 34 PP left, got %d", defender.Moves[0].PP)
+	}
+}
+
+func TestBattleStruggle(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	struggler := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 90}, Moves: NewMoveSlots([4]Move{tackle})}
+	defender := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 45}}
+	struggler.Moves[0].PP = 0
+
+	battle := NewBattle(&MockBattler{Pokemon: struggler, Action: BattleAction{Type: Attack}}, &MockBattler{Pokemon: defender, Action: BattleAction{Type: Attack}})
+	battle.MaxTurns = 1
+	battle.Run()
+
+	if battle.Log.Messages[0] != "Charmander used Struggle!" {
+		t.Fatalf("Expected a Pokemon without PP to Struggle, log %q", battle.Log.Messages)
+	}
+	if defender.Health.Current == 100 || struggler.Health.Current == 100 {
+		t.Errorf("Struggle should hurt both sides, health %d and %d", struggler.Health.Current, defender.Health.Current)
+	}
+	if struggler.Moves[0].PP != 0 {
+		t.Errorf("Struggle shouldn't touch PP, Tackle has %d", struggler.Moves[0].PP)
+	}
+}
+
+func TestBattleSleepPreventsAction(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	sleeper := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 90}, Moves: NewMoveSlots([4]Move{tackle})}
+	other := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 50}, Moves: NewMoveSlots([4]Move{tackle})}
+	sleeper.StatusManager.Primary = &SleepStatus{Duration: 2}
+
+	battle := NewBattle(&MockBattler{Pokemon: sleeper, Action: BattleAction{Type: Attack}}, &MockBattler{Pokemon: other, Action: BattleAction{Type: Attack}})
+	battle.MaxTurns = 1
+	battle.Run()
+
+	if other.Health.Current != 100 || sleeper.Moves[0].PP != 35 {
+		t.Errorf("A sleeping Pokemon shouldn't attack or spend PP, health %d, PP %d", other.Health.Current, sleeper.Moves[0].PP)
+	}
+	want := []string{"Charmander is fast asleep.", "Bulbasaur used Tackle!"}
+	if !reflect.DeepEqual(battle.Log.Messages, want) {
+		t.Errorf("Log = %q, want %q", battle.Log.Messages, want)
+	}
+	if sleep, ok := sleeper.StatusManager.Primary.(*SleepStatus); !ok || sleep.Duration != 1 {
+		t.Errorf("Expected one more turn of sleep, got %+v", sleeper.StatusManager.Primary)
+	}
+}
+
+func TestBattleEndConditions(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	splash := Move{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}
+	newPokemon := func(species *Species, hp, speed int) *Pokemon {
+		return &Pokemon{Species: species, Level: 10, Health: Health{Current: hp, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: speed}, Moves: NewMoveSlots([4]Move{tackle, splash})}
+	}
+	attack := BattleAction{Type: Attack}
+
+	tests := []struct {
+		name       string
+		action1    BattleAction
+		action2    BattleAction
+		hp1, hp2   int
+		maxTurns   int
+		opponent   string // "trainer" or "wild", otherwise a MockBattler
+		wantWinner int    // 0 for none, otherwise which battler won
+		wantReason EndReason
+		wantTurns  int
+	}{
+		{"opponent faints", attack, attack, 100, 1, 0, "", 1, AllFainted, 1},
+		{"fainted over several turns", attack, BattleAction{Type: Attack, MoveSlot: 1}, 100, 20, 0, "", 1, AllFainted, 4},
+		{"flee", BattleAction{Type: Flee}, attack, 100, 100, 0, "wild", 0, Fled, 1},
+		{"no fleeing from a trainer", BattleAction{Type: Flee}, attack, 100, 100, 2, "", 0, TurnLimit, 2},
+		{"forfeit", attack, BattleAction{Type: Forfeit}, 100, 100, 0, "", 1, Forfeited, 1},
+		{"turn limit", BattleAction{Type: Attack, MoveSlot: 1}, BattleAction{Type: Attack, MoveSlot: 1}, 100, 100, 3, "", 0, TurnLimit, 3},
+		{"trainer out of pokemon", attack, attack, 100, 1, 0, "trainer", 1, AllFainted, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			battler1 := &MockBattler{Pokemon: newPokemon(CharmanderSpecies, tt.hp1, 90), Action: tt.action1}
+			var battler2 Battler = &MockBattler{Pokemon: newPokemon(BulbasaurSpecies, tt.hp2, 45), Action: tt.action2}
+			switch tt.opponent {
+			case "trainer":
+				battler2 = NewTrainer("Brock", [TeamSize]*Pokemon{newPokemon(BulbasaurSpecies, tt.hp2, 45)})
+			case "wild":
+				battler2 = &WildPokemon{Pokemon: newPokemon(BulbasaurSpecies, tt.hp2, 45)}
+			}
+
+			battle := NewBattle(battler1, battler2)
+			if tt.maxTurns > 0 {
+				battle.MaxTurns = tt.maxTurns
+			}
+			result := battle.Run()
+
+			var wantWinner Battler
+			switch tt.wantWinner {
+			case 1:
+				wantWinner = battler1
+			case 2:
+				wantWinner = battler2
+			}
+			if result.Winner != wantWinner {
+				t.Errorf("winner = %v, want %v", result.Winner, wantWinner)
+			}
+			if result.Reason != tt.wantReason {
+				t.Errorf("reason = %v, want %v", result.Reason, tt.wantReason)
+			}
+			if result.Turns != tt.wantTurns {
+				t.Errorf("turns = %d, want %d", result.Turns, tt.wantTurns)
+			}
+			if battle.Running {
+				t.Error("battle still running after Run")
+			}
+		})
+	}
+}
+
+func TestBattleRewardsRecoilFaint(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	splash := Move{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 50, PP: 35, Accuracy: 100}
+	winnerSpecies := &Species{Name: "Machop", BaseStats: Stats{HP: 70, Attack: 80, Defense: 50, SpecialAttack: 35, SpecialDefense: 35, Speed: 35}}
+	loserSpecies := &Species{Name: "Caterpie", EVYield: Stats{HP: 1}, BaseExpYield: 39}
+
+	winner := NewPokemon(winnerSpecies, 50, nil, nil, [4]Move{splash})
+	// out of PP, so the slower Caterpie Struggles and knocks itself out with the recoil
+	loser := &Pokemon{Species: loserSpecies, Level: 2, Health: Health{Current: 1, Max: 4}, Stats: Stats{Attack: 5, Defense: 5, Speed: 1}, Moves: NewMoveSlots([4]Move{tackle})}
+	loser.Moves[0].PP = 0
+
+	battle := NewBattle(&MockBattler{Pokemon: winner, Action: BattleAction{Type: Attack}}, &MockBattler{Pokemon: loser, Action: BattleAction{Type: Attack}})
+	result := battle.Run()
+
+	want := []string{"Machop used Splash!", "Caterpie used Struggle!", "Caterpie fainted!"}
+	if !reflect.DeepEqual(battle.Log.Messages, want) {
+		t.Errorf("log = %q, want %q", battle.Log.Messages, want)
+	}
+	if result.Reason != AllFainted || result.Winner == nil || result.Winner.GetPokemon() != winner {
+		t.Errorf("result = %+v, want Machop to win", result)
+	}
+	if winner.EVs().HP != 1 || winner.Experience != 125000+16 {
+		t.Errorf("Machop gained EVs %v and %d experience, want 1 HP EV and 16", winner.EVs(), winner.Experience-125000)
+	}
+}
+
+func TestBattleBothSidesFaint(t *testing.T) {
+	poisoned := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 1, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 90}}
+	poisoned.StatusManager.Primary = &PoisonStatus{}
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	poisoned.Moves = NewMoveSlots([4]Move{tackle})
+	other := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 1, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 45}}
+	other.Moves = NewMoveSlots([4]Move{{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}})
+	setRandomizer(t, highRolls)
+
+	// Charmander knocks Bulbasaur out, then faints to poison at the end of the turn.
+	battle := NewBattle(&MockBattler{Pokemon: poisoned, Action: BattleAction{Type: Attack}}, &MockBattler{Pokemon: other, Action: BattleAction{Type: Attack}})
+	result := battle.Run()
+
+	if result.Winner != nil || result.Reason != AllFainted || result.Turns != 1 {
+		t.Errorf("result = %+v, want a draw after 1 turn", result)
+	}
+}
+
+func TestBattleCapture(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	wild := &Pokemon{Species: BulbasaurSpecies, Level: 5, Health: Health{Current: 20, Max: 20}, Stats: Stats{Speed: 45}}
+	wild.Moves = NewMoveSlots([4]Move{{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}})
+	caught := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 30, Max: 30}, Stats: Stats{Speed: 65}}
+	trainer := NewTrainer("Ash", [6]*Pokemon{caught})
+	ball := &MockBattler{Pokemon: caught, Action: BattleAction{Type: UseItem, Item: MasterBall}}
+
+	battle := NewBattle(ball, &WildPokemon{Pokemon: wild})
+	result := battle.Run()
+	if result.Reason != Captured || result.Winner != ball || result.Turns != 1 {
+		t.Errorf("result = %+v, want capture by the thrower on turn 1", result)
+	}
+	if want := []string{"Gotcha! Bulbasaur was caught!"}; !reflect.DeepEqual(battle.Log.Messages, want) {
+		t.Errorf("log = %q, want %q", battle.Log.Messages, want)
+	}
+
+	// Trainers always attack for now, so their catch goes through throwBall directly.
+	battle = NewBattle(trainer, &WildPokemon{Pokemon: wild})
+	battle.throwBall(trainer, MasterBall, battle.Battler2)
+	if trainer.Team[1] != wild {
+		t.Errorf("trainer team = %v, want the caught Pokemon added", trainer.Team)
+	}
+}
+
+func TestBattleCaptureBlockedByTrainer(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	splash := Move{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}
+	thrower := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 30, Max: 30}, Stats: Stats{Speed: 65}, Moves: NewMoveSlots([4]Move{splash})}
+	owned := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 30, Max: 30}, Stats: Stats{Speed: 45}, Moves: NewMoveSlots([4]Move{splash})}
+	opponent := &MockBattler{Pokemon: owned, Action: BattleAction{Type: Attack}}
+
+	battle := NewBattle(&MockBattler{Pokemon: thrower, Action: BattleAction{Type: UseItem, Item: MasterBall}}, opponent)
+	battle.MaxTurns = 1
+	result := battle.Run()
+
+	if result.Reason != TurnLimit {
+		t.Errorf("reason = %v, want %v", result.Reason, TurnLimit)
+	}
+	if want := []string{"The trainer blocked the Master Ball!", "Bulbasaur used Splash!"}; !reflect.DeepEqual(battle.Log.Messages, want) {
+		t.Errorf("log = %q, want %q", battle.Log.Messages, want)
+	}
+}
+
+// PickyTrainer always asks for the same replacement slot.
+type PickyTrainer struct {
+	*Trainer
+	Slot int
+}
+
+func (pt *PickyTrainer) ChooseReplacement() int {
+	return pt.Slot
+}
+
+func TestBattleReplacesFaintedPokemon(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	splash := Move{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}
+	newPokemon := func(species *Species, hp int) *Pokemon {
+		return &Pokemon{Species: species, Level: 10, Health: Health{Current: hp, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 45}, Moves: NewMoveSlots([4]Move{splash})}
+	}
+	squirtle := &Species{Name: "Squirtle"}
+
+	tests := []struct {
+		name       string
+		slot       int // replacement the trainer asks for, 0 to use Trainer's own choice
+		bench      []*Pokemon
+		wantActive string
+		wantReason EndReason
+	}{
+		{"first healthy", 0, []*Pokemon{newPokemon(BulbasaurSpecies, 0), newPokemon(squirtle, 100)}, "Squirtle", TurnLimit},
+		{"chosen slot", 2, []*Pokemon{newPokemon(BulbasaurSpecies, 100), newPokemon(squirtle, 100)}, "Squirtle", TurnLimit},
+		{"fainted choice falls back", 1, []*Pokemon{newPokemon(BulbasaurSpecies, 0), newPokemon(squirtle, 100)}, "Squirtle", TurnLimit},
+		{"out of range choice falls back", 9, []*Pokemon{newPokemon(BulbasaurSpecies, 100)}, "Bulbasaur", TurnLimit},
+		{"nobody left", 0, []*Pokemon{newPokemon(BulbasaurSpecies, 0)}, "Charmander", AllFainted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lead := newPokemon(CharmanderSpecies, 1)
+			lead.Modifiers.Attack = 2
+			var team [TeamSize]*Pokemon
+			team[0] = lead
+			copy(team[1:], tt.bench)
+			var trainer Battler = NewTrainer("Misty", team)
+			if tt.slot != 0 {
+				trainer = &PickyTrainer{Trainer: trainer.(*Trainer), Slot: tt.slot}
+			}
+			opponent := &Pokemon{Species: &Species{Name: "Rattata"}, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 90}, Moves: NewMoveSlots([4]Move{tackle})}
+
+			battle := NewBattle(trainer, &MockBattler{Pokemon: opponent, Action: BattleAction{Type: Attack}})
+			battle.MaxTurns = 2
+			result := battle.Run()
+
+			if result.Reason != tt.wantReason {
+				t.Errorf("reason = %v, want %v", result.Reason, tt.wantReason)
+			}
+			if got := trainer.GetPokemon().Name(); got != tt.wantActive {
+				t.Errorf("active Pokemon = %s, want %s", got, tt.wantActive)
+			}
+			if tt.wantReason == TurnLimit && battle.Log.Messages[2] != "Go! "+tt.wantActive+"!" {
+				t.Errorf("log = %q, want the replacement sent out after the faint", battle.Log.Messages)
+			}
+			if lead.Modifiers.Attack != 0 {
+				t.Errorf("fainted lead kept its attack stage %d", lead.Modifiers.Attack)
+			}
+		})
+	}
+}
+
+// ScriptedTrainer is a trainer that always chooses Action.
+type ScriptedTrainer struct {
+	*Trainer
+	Action BattleAction
+}
+
+func (st *ScriptedTrainer) ChooseAction() BattleAction {
+	return st.Action
+}
+
+func TestBattleSwitchPokemon(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	quickAttack := Move{Name: "Quick Attack", Category: Physical, Power: 40, PP: 30, Accuracy: 100, Priority: 1}
+	tests := []struct {
+		name       string
+		trapped    bool
+		slot       int
+		wantActive string
+		wantLog    []string
+	}{
+		{"switches before moves", false, 1, "Squirtle", []string{"Come back, Charmander!", "Go! Squirtle!", "Rattata used Quick Attack!"}},
+		{"trapped", true, 1, "Charmander", []string{"Charmander can't be switched out!", "Rattata used Quick Attack!", "Charmander is hurt by the bind!"}},
+		{"empty slot", false, 3, "Charmander", []string{"Charmander can't switch with that Pokemon!", "Rattata used Quick Attack!"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lead := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 10}}
+			lead.Modifiers.Defense = -1
+			bench := &Pokemon{Species: &Species{Name: "Squirtle"}, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 10}}
+			opponent := &Pokemon{Species: &Species{Name: "Rattata"}, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 90}, Moves: NewMoveSlots([4]Move{quickAttack})}
+			if tt.trapped {
+				lead.StatusManager.Inflict(lead, &BoundStatus{Source: opponent, Duration: 4})
+			}
+
+			trainer := &ScriptedTrainer{Trainer: NewTrainer("Misty", [TeamSize]*Pokemon{lead, bench}), Action: BattleAction{Type: SwitchPokemon, SwitchTo: tt.slot}}
+			battle := NewBattle(trainer, &MockBattler{Pokemon: opponent, Action: BattleAction{Type: Attack}})
+			battle.MaxTurns = 1
+			battle.Run()
+
+			if got := trainer.GetPokemon().Name(); got != tt.wantActive {
+				t.Errorf("active Pokemon = %s, want %s", got, tt.wantActive)
+			}
+			if !reflect.DeepEqual(battle.Log.Messages, tt.wantLog) {
+				t.Errorf("log = %q, want %q", battle.Log.Messages, tt.wantLog)
+			}
+			if tt.wantActive == "Squirtle" && (lead.Modifiers.Defense != 0 || lead.Health.Current != 100 || bench.Health.Current == 100) {
+				t.Errorf("switch-in should take the hit and the lead leave with its stages reset, lead %+v", lead)
+			}
+		})
+	}
+}
+
+func TestBattleUseItem(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	splash := Move{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}
+	tests := []struct {
+		name     string
+		items    []Item
+		benchHP  int
+		wantHP   int
+		wantBag  int
+		wantLast string
+	}{
+		{"heals the target slot", []Item{OranBerry}, 50, 60, 0, "Used the Oran Berry on Squirtle!"},
+		{"not in the bag", nil, 50, 50, 0, "Misty has no Oran Berry"},
+		{"no effect keeps the item", []Item{OranBerry}, 100, 100, 1, "Cannot heal a pokemon with full health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lead := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 10}}
+			bench := &Pokemon{Species: &Species{Name: "Squirtle"}, Level: 10, Health: Health{Current: tt.benchHP, Max: 100}}
+			opponent := &Pokemon{Species: &Species{Name: "Rattata"}, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 90}, Moves: NewMoveSlots([4]Move{splash})}
+
+			trainer := &ScriptedTrainer{Trainer: NewTrainer("Misty", [TeamSize]*Pokemon{lead, bench}), Action: BattleAction{Type: UseItem, Item: OranBerry, Target: 1}}
+			trainer.Items = tt.items
+			battle := NewBattle(trainer, &MockBattler{Pokemon: opponent, Action: BattleAction{Type: Attack}})
+			battle.MaxTurns = 1
+			battle.Run()
+
+			if bench.Health.Current != tt.wantHP {
+				t.Errorf("Squirtle HP = %d, want %d", bench.Health.Current, tt.wantHP)
+			}
+			if len(trainer.Items) != tt.wantBag {
+				t.Errorf("bag = %v, want %d items", trainer.Items, tt.wantBag)
+			}
+			// the item goes before Rattata's faster move
+			if want := []string{tt.wantLast, "Rattata used Splash!"}; !reflect.DeepEqual(battle.Log.Messages, want) {
+				t.Errorf("log = %q, want %q", battle.Log.Messages, want)
+			}
+		})
+	}
+}
+
+func TestBattleUseBerryKeepsHeldItem(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	splash := Move{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}
+	lead := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 50, Max: 100}, Stats: Stats{Speed: 10}, HeldItem: LuckyEgg}
+	opponent := &Pokemon{Species: &Species{Name: "Rattata"}, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 90}, Moves: NewMoveSlots([4]Move{splash})}
+
+	trainer := &ScriptedTrainer{Trainer: NewTrainer("Misty", [TeamSize]*Pokemon{lead}), Action: BattleAction{Type: UseItem, Item: OranBerry}}
+	trainer.Items = []Item{OranBerry}
+	battle := NewBattle(trainer, &MockBattler{Pokemon: opponent, Action: BattleAction{Type: Attack}})
+	battle.MaxTurns = 1
+	battle.Run()
+
+	if lead.Health.Current != 60 || lead.HeldItem != LuckyEgg {
+		t.Errorf("Oran Berry from the bag left HP %d and held item %v, want 60 and the Lucky Egg", lead.Health.Current, lead.HeldItem)
+	}
+}
+
+func TestBattleUseMoveItem(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	splash := Move{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	tests := []struct {
+		name     string
+		item     Item
+		benchHP  int
+		tacklePP int
+		wantPP   int
+		wantUps  int
+		wantBag  int
+		wantLast string
+	}{
+		{"ether at full HP", Ether, 100, 5, 15, 0, 0, "Used the Ether on Squirtle!"},
+		{"ether on full PP", Ether, 90, 35, 35, 0, 1, "Ether had no effect on Squirtle"},
+		{"pp up", PPUp, 100, 35, 42, 1, 0, "Used the PP Up on Squirtle!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lead := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 10}}
+			bench := &Pokemon{Species: &Species{Name: "Squirtle"}, Level: 10, Health: Health{Current: tt.benchHP, Max: 100}, Moves: NewMoveSlots([4]Move{splash, tackle})}
+			bench.Moves[1].PP = tt.tacklePP
+			opponent := &Pokemon{Species: &Species{Name: "Rattata"}, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 90}, Moves: NewMoveSlots([4]Move{splash})}
+
+			trainer := &ScriptedTrainer{Trainer: NewTrainer("Misty", [TeamSize]*Pokemon{lead, bench}), Action: BattleAction{Type: UseItem, Item: tt.item, Target: 1, MoveSlot: 1}}
+			trainer.Items = []Item{tt.item}
+			battle := NewBattle(trainer, &MockBattler{Pokemon: opponent, Action: BattleAction{Type: Attack}})
+			battle.MaxTurns = 1
+			battle.Run()
+
+			if bench.Moves[1].PP != tt.wantPP || bench.Moves[1].PPUps != tt.wantUps || bench.Moves[0].PP != 40 {
+				t.Errorf("moves = %+v, want Tackle at %d PP with %d PP Ups", bench.Moves[:2], tt.wantPP, tt.wantUps)
+			}
+			if len(trainer.Items) != tt.wantBag {
+				t.Errorf("bag = %v, want %d items", trainer.Items, tt.wantBag)
+			}
+			if want := []string{tt.wantLast, "Rattata used Splash!"}; !reflect.DeepEqual(battle.Log.Messages, want) {
+				t.Errorf("log = %q, want %q", battle.Log.Messages, want)
+			}
+		})
+	}
+}
+
+func TestBattleFlee(t *testing.T) {
+	splash := Move{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}
+
+	// At half the wild Pokemon's speed the chance is 64 out of 256, plus 30 per attempt.
+	tests := []struct {
+		name       string
+		speed      int
+		trapped    bool
+		roll       int
+		wantReason EndReason
+		wantTurns  int
+	}{
+		{"faster always escapes", 90, false, 255, Fled, 1},
+		{"slower escapes under the chance", 45, false, 93, Fled, 1},
+		{"slower fails over the chance", 45, false, 200, TurnLimit, 3},
+		{"attempts add up", 45, false, 100, Fled, 2},
+		{"trapped", 90, true, 0, TurnLimit, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRandomizer(t, &MockRand{IntnFunc: func(n int) int { return min(tt.roll, n-1) }})
+			runner := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: tt.speed}}
+			wild := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 90}, Moves: NewMoveSlots([4]Move{splash})}
+			if tt.trapped {
+				runner.StatusManager.Secondary = []StatusEffect{&BoundStatus{Source: wild, Duration: 10}}
+			}
+
+			battle := NewBattle(&MockBattler{Pokemon: runner, Action: BattleAction{Type: Flee}}, &WildPokemon{Pokemon: wild})
+			battle.MaxTurns = 3
+			result := battle.Run()
+
+			if result.Reason != tt.wantReason || result.Turns != tt.wantTurns {
+				t.Errorf("result = %v after %d turns, want %v after %d", result.Reason, result.Turns, tt.wantReason, tt.wantTurns)
+			}
+		})
+	}
+}
+
+func TestBattlersFollowSameRules(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	newPokemon := func() *Pokemon {
+		return &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 45}, Moves: NewMoveSlots([4]Move{tackle})}
+	}
+	trainer := NewTrainer("Erika", [TeamSize]*Pokemon{newPokemon()})
+	wild := &WildPokemon{Pokemon: newPokemon()}
+
+	battle := NewBattle(trainer, wild)
+	battle.MaxTurns = 1
+	battle.Run()
+
+	if trainer.Team[0].Health.Current != wild.Pokemon.Health.Current || wild.Pokemon.Health.Current == 100 {
+		t.Errorf("A trainer and a wild Pokemon should hit equally hard, health %d and %d", trainer.Team[0].Health.Current, wild.Pokemon.Health.Current)
+	}
+	if want := []string{"Bulbasaur used Tackle!", "Bulbasaur used Tackle!"}; !reflect.DeepEqual(battle.Log.Messages, want) {
+		t.Errorf("log = %q, want %q", battle.Log.Messages, want)
+	}
+}
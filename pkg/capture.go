@@ -0,0 +1,64 @@
+package pokemon
+
+// defaultCaptureRate stands in for species whose data has no capture rate.
+const defaultCaptureRate = 45
+
+// CaptureItem is an item thrown at a wild Pokemon in battle to catch it.
+type CaptureItem interface {
+	Item
+	Catch(target *Pokemon) bool
+}
+
+// Ball catches wild Pokemon, the more easily the weaker they are. A ball without a
+// bonus never fails, like the Master Ball.
+type Ball struct {
+	id    ItemID
+	name  string
+	bonus float64
+}
+
+// Use does nothing, balls are only thrown in battle.
+func (b *Ball) Use(p *Pokemon) {}
+
+// Catch rolls whether target is caught, with the chance out of 255 being
+// (3*max HP - 2*HP) * capture rate * ball bonus / (3*max HP), doubled by sleep or
+// freeze and raised by half for the other statuses.
+func (b *Ball) Catch(target *Pokemon) bool {
+	if b.bonus == 0 {
+		return true
+	}
+	rate := defaultCaptureRate
+	if target.Species != nil && target.Species.CaptureRate > 0 {
+		rate = target.Species.CaptureRate
+	}
+
+	maxHP := max(target.Health.Max, 1)
+	chance := float64(3*maxHP-2*target.Health.Current) * float64(rate) * b.bonus / float64(3*maxHP)
+	chance *= captureStatusBonus(target)
+	return chance >= 255 || float64(rng.Intn(255)) < chance
+}
+
+func captureStatusBonus(p *Pokemon) float64 {
+	switch p.StatusManager.Primary.(type) {
+	case nil, *FaintedStatus:
+		return 1
+	case *SleepStatus, *FreezeStatus:
+		return 2
+	}
+	return 1.5
+}
+
+func (b *Ball) Name() string {
+	return b.name
+}
+
+func (b *Ball) ID() ItemID {
+	return b.id
+}
+
+var (
+	PokeBall   = &Ball{id: "poke-ball", name: "Poke Ball", bonus: 1}
+	GreatBall  = &Ball{id: "great-ball", name: "Great Ball", bonus: 1.5}
+	UltraBall  = &Ball{id: "ultra-ball", name: "Ultra Ball", bonus: 2}
+	MasterBall = &Ball{id: "master-ball", name: "Master Ball"}
+)
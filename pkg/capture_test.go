@@ -0,0 +1,39 @@
+package pokemon
+
+import "testing"
+
+func TestBallCatch(t *testing.T) {
+	species := &Species{Name: "Test", CaptureRate: 45}
+	newTarget := func(hp int, status StatusEffect) *Pokemon {
+		p := &Pokemon{Species: species, Health: Health{Current: hp, Max: 100}}
+		p.StatusManager.Primary = status
+		return p
+	}
+
+	// At full HP a Poke Ball's chance is 45 * 100/300 = 15 out of 255.
+	tests := []struct {
+		name   string
+		ball   *Ball
+		target *Pokemon
+		roll   int
+		want   bool
+	}{
+		{"poke ball full hp under chance", PokeBall, newTarget(100, nil), 14, true},
+		{"poke ball full hp over chance", PokeBall, newTarget(100, nil), 15, false},
+		{"ultra ball doubles chance", UltraBall, newTarget(100, nil), 29, true},
+		{"low hp triples chance", PokeBall, newTarget(1, nil), 44, true},
+		{"sleep doubles chance", PokeBall, newTarget(100, &SleepStatus{Duration: 2}), 29, true},
+		{"paralysis adds half", PokeBall, newTarget(100, &ParalysisStatus{}), 22, true},
+		{"paralysis adds only half", PokeBall, newTarget(100, &ParalysisStatus{}), 23, false},
+		{"master ball never fails", MasterBall, newTarget(100, nil), 254, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRandomizer(t, &MockRand{IntnFunc: func(n int) int { return tt.roll }})
+			if got := tt.ball.Catch(tt.target); got != tt.want {
+				t.Errorf("Catch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package pokemon
+
+const (
+	confusionPower        = 40
+	criticalHitChance     = 24 // 1 in 24
+	criticalHitMultiplier = 1.5
+	stabMultiplier        = 1.5
+)
+
+// DamageResult describes the outcome of a single move hitting its target.
+type DamageResult struct {
+	Damage        int
+	Missed        bool
+	Critical      bool
+	STAB          bool
+	Effectiveness float64
+	Hits          int  // set by Move.Execute
+	Substitute    bool // set by Move.Execute when the target's substitute took the hits
+}
+
+// CalculateDamage works out how much damage move would do to target without applying it.
+func CalculateDamage(user *Pokemon, target *Pokemon, move *Move) DamageResult {
+	return calculateDamage(user, target, move, DefaultTypeChart)
+}
+
+func calculateDamage(user *Pokemon, target *Pokemon, move *Move, chart *TypeChart) DamageResult {
+	result := DamageResult{Effectiveness: 1.0}
+	// status moves get the matchup too, so an immune target shrugs off the status they inflict
+	if target.Species != nil && !move.Typeless && !move.TargetsUser {
+		result.Effectiveness = chart.Effectiveness(move.Type, target.Species.Types)
+	}
+	if move.Power <= 0 || result.Effectiveness == 0 {
+		return result
+	}
+
+	attack, defense := attackingStats(user, target, move.Category)
+
+	base := ((2*user.Level/5+2)*move.Power*attack/defense)/50 + 2
+
+	if rng.Intn(criticalHitChance) == 0 {
+		result.Critical = true
+		base = int(float64(base) * criticalHitMultiplier)
+	}
+
+	// random roll between 85% and 100%
+	base = base * (85 + rng.Intn(16)) / 100
+
+	if user.Species != nil && !move.Typeless && hasType(user.Species.Types, move.Type) {
+		result.STAB = true
+		base = int(float64(base) * stabMultiplier)
+	}
+
+	base = int(float64(base) * result.Effectiveness)
+
+	if base < 1 {
+		base = 1
+	}
+
+	result.Damage = base
+	return result
+}
+
+// confusionDamage is the typeless physical hit a confused Pokemon deals itself, it
+// can't be a critical hit.
+func confusionDamage(p *Pokemon) int {
+	attack, defense := attackingStats(p, p, Physical)
+	base := ((2*p.Level/5+2)*confusionPower*attack/defense)/50 + 2
+	return atLeastOne(base * (85 + rng.Intn(16)) / 100)
+}
+
+// attackingStats picks the attack and defense stat used for the move category, with stat stages applied.
+func attackingStats(user *Pokemon, target *Pokemon, category MoveCategory) (int, int) {
+	var attack, defense float64
+	switch category {
+	case Special:
+		attack = user.stagedStat(StatSpecialAttack)
+		defense = target.stagedStat(StatSpecialDefense)
+	default:
+		attack = user.stagedStat(StatAttack)
+		defense = target.stagedStat(StatDefense)
+		if user.isBurned() {
+			attack /= 2
+		}
+	}
+
+	if defense < 1 {
+		defense = 1
+	}
+	return int(attack), int(defense)
+}
+
+func hasType(types []Type, t Type) bool {
+	for _, typ := range types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package pokemon
+
+import "testing"
+
+func newDamageTestPokemon(types []Type) *Pokemon {
+	return &Pokemon{
+		Species: &Species{Name: "Test", Types: types},
+		Level:   50,
+		Health:  Health{Current: 200, Max: 200},
+		Stats:   Stats{HP: 200, Attack: 100, Defense: 100, SpecialAttack: 50, SpecialDefense: 100, Speed: 50},
+	}
+}
+
+func TestCalculateDamage(t *testing.T) {
+	tackle := Move{Name: "Strike", Type: Normal, Category: Physical, Power: 80, Accuracy: 100}
+	ember := Move{Name: "Blaze", Type: Fire, Category: Physical, Power: 80, Accuracy: 100}
+	beam := Move{Name: "Beam", Type: Normal, Category: Special, Power: 80, Accuracy: 100}
+	growl := Move{Name: "Growl", Type: Normal, Category: Physical, Power: 0, Accuracy: 100}
+
+	critRolls := &MockRand{IntnFunc: func(n int) int {
+		if n == criticalHitChance {
+			return 0
+		}
+		return n - 1
+	}}
+	lowRolls := &MockRand{IntnFunc: func(n int) int {
+		if n == 16 {
+			return 0
+		}
+		return n - 1
+	}}
+
+	tests := []struct {
+		name     string
+		move     Move
+		rand     Randomizer
+		attacker func(p *Pokemon)
+		want     DamageResult
+	}{
+		{"physical", tackle, highRolls, nil, DamageResult{Damage: 37, Effectiveness: 1}},
+		{"stab", ember, highRolls, nil, DamageResult{Damage: 55, STAB: true, Effectiveness: 1}},
+		{"special", beam, highRolls, nil, DamageResult{Damage: 19, Effectiveness: 1}},
+		{"critical", tackle, critRolls, nil, DamageResult{Damage: 55, Critical: true, Effectiveness: 1}},
+		{"lowest roll", tackle, lowRolls, nil, DamageResult{Damage: 31, Effectiveness: 1}},
+		{"attack stage", tackle, highRolls, func(p *Pokemon) { p.Modifiers.Attack = 2 }, DamageResult{Damage: 72, Effectiveness: 1}},
+		{"status move", growl, highRolls, nil, DamageResult{Effectiveness: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRandomizer(t, tt.rand)
+			user := newDamageTestPokemon([]Type{Fire})
+			target := newDamageTestPokemon([]Type{Normal})
+			if tt.attacker != nil {
+				tt.attacker(user)
+			}
+
+			if got := CalculateDamage(user, target, &tt.move); got != tt.want {
+				t.Errorf("CalculateDamage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveExecuteAppliesDamage(t *testing.T) {
+	setRandomizer(t, highRolls)
+	user := newDamageTestPokemon([]Type{Fire})
+	target := newDamageTestPokemon([]Type{Normal})
+	move := Move{Name: "Strike", Type: Normal, Category: Physical, Power: 80, Accuracy: 100}
+
+	result := move.Execute(user, target)
+
+	if result.Missed {
+		t.Fatalf("Execute() missed with a 100%% accurate move")
+	}
+	if target.Health.Current != 200-result.Damage {
+		t.Errorf("Execute() target health = %d, want %d", target.Health.Current, 200-result.Damage)
+	}
+}
+
+func TestMoveExecuteMiss(t *testing.T) {
+	setRandomizer(t, highRolls)
+	user := newDamageTestPokemon([]Type{Fire})
+	target := newDamageTestPokemon([]Type{Normal})
+	move := Move{Name: "Strike", Type: Normal, Category: Physical, Power: 80, Accuracy: 50}
+
+	result := move.Execute(user, target)
+
+	if !result.Missed {
+		t.Errorf("Execute() expected a miss, got %+v", result)
+	}
+	if target.Health.Current != 200 {
+		t.Errorf("Execute() target took damage on a miss, health = %d", target.Health.Current)
+	}
+}
@@ -0,0 +1,32 @@
+package pokemon
+
+import "embed"
+
+// The dataset is generated from a PokeAPI CSV dump, see cmd/pokedexgen.
+//go:generate go run ../cmd/pokedexgen -csv $POKEAPI_CSV -out data -version $DATASET_VERSION
+
+//go:embed data/pokedex.json data/moves.json
+var dataset embed.FS
+
+func defaultMoveDex() *MoveDex {
+	moveDex, err := LoadMoveDexFromFS(dataset, "data/moves.json")
+	if err != nil {
+		panic("pokemon: embedded move data: " + err.Error())
+	}
+	return moveDex
+}
+
+// DefaultMoveRegistry holds every move in the embedded dataset. Learnsets are resolved
+// against it when loading a Pokedex.
+var DefaultMoveRegistry MoveRegistry = defaultMoveDex()
+
+// LoadDefaultMoveDex loads the embedded move dataset.
+func LoadDefaultMoveDex() (*MoveDex, error) {
+	return LoadMoveDexFromFS(dataset, "data/moves.json")
+}
+
+// LoadDefaultPokedex loads the embedded species dataset, whichever species the dump
+// it was generated from covers.
+func LoadDefaultPokedex() (*Pokedex, error) {
+	return LoadPokedexFromFS(dataset, "data/pokedex.json")
+}
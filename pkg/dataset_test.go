@@ -0,0 +1,93 @@
+package pokemon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadDefaultPokedex(t *testing.T) {
+	pokedex, err := LoadDefaultPokedex()
+	if err != nil {
+		t.Fatalf("LoadDefaultPokedex() error = %v", err)
+	}
+	if pokedex.Version == "" {
+		t.Errorf("Expected the embedded dataset to be versioned")
+	}
+
+	pikachu := pokedex.GetSpeciesByID(25)
+	if pikachu == nil || pikachu.Name != "Pikachu" || !reflect.DeepEqual(pikachu.Types, []Type{Electric}) {
+		t.Fatalf("GetSpeciesByID(25) = %+v, want Pikachu", pikachu)
+	}
+	if pikachu.BaseStats.Speed != 90 || pikachu.BaseExpYield != 112 || pikachu.EVYield.Speed != 2 {
+		t.Errorf("Pikachu base stats %+v, exp yield %d, EV yield %+v", pikachu.BaseStats, pikachu.BaseExpYield, pikachu.EVYield)
+	}
+
+	moves := pikachu.Learnset.MovesAt(1)
+	if len(moves) == 0 || moves[0].Name != "Thunder Shock" || moves[0].Power != 40 || moves[0].Type != Electric {
+		t.Errorf("Pikachu's first move = %+v, want a resolved Thunder Shock", moves)
+	}
+
+	if len(pokedex.GetSpeciesByID(133).EvolutionStages) != 3 {
+		t.Errorf("Eevee should have 3 evolutions, got %+v", pokedex.GetSpeciesByID(133).EvolutionStages)
+	}
+}
+
+func TestDefaultPokedexIsConsistent(t *testing.T) {
+	pokedex, err := LoadDefaultPokedex()
+	if err != nil {
+		t.Fatalf("LoadDefaultPokedex() error = %v", err)
+	}
+
+	for _, species := range pokedex.Species {
+		if len(species.Types) == 0 || species.BaseStats.HP == 0 || species.BaseExpYield == 0 {
+			t.Errorf("%s is missing types, stats or experience yield", species.Name)
+		}
+		if species.CaptureRate == 0 {
+			t.Errorf("%s has no capture rate", species.Name)
+		}
+		if len(species.Learnset) == 0 {
+			t.Errorf("%s has no learnset", species.Name)
+		}
+		for _, stage := range species.EvolutionStages {
+			if pokedex.GetSpeciesByID(stage.EvolvesInto) == nil {
+				t.Errorf("%s evolves into unknown species %d", species.Name, stage.EvolvesInto)
+			}
+		}
+	}
+}
+
+func TestDefaultPokedexCaptureRates(t *testing.T) {
+	pokedex, err := LoadDefaultPokedex()
+	if err != nil {
+		t.Fatalf("LoadDefaultPokedex() error = %v", err)
+	}
+
+	tests := []struct {
+		id   int
+		want int
+	}{
+		{1, 45},   // Bulbasaur
+		{10, 255}, // Caterpie
+		{143, 25}, // Snorlax
+		{150, 3},  // Mewtwo
+	}
+	for _, tt := range tests {
+		species := pokedex.GetSpeciesByID(tt.id)
+		if species.CaptureRate != tt.want {
+			t.Errorf("%s capture rate = %d, want %d", species.Name, species.CaptureRate, tt.want)
+		}
+	}
+}
+
+func TestDatasetEvolution(t *testing.T) {
+	pokedex, err := LoadDefaultPokedex()
+	if err != nil {
+		t.Fatalf("LoadDefaultPokedex() error = %v", err)
+	}
+
+	charmander := NewPokemon(pokedex.GetSpeciesByID(4), 16, nil, nil, [4]Move{})
+	event, err := NewEvolutionEngine(pokedex).AfterLevelUp(charmander, EvolutionContext{})
+	if err != nil || event == nil || charmander.Species.Name != "Charmeleon" {
+		t.Errorf("Charmander at level 16 should evolve into Charmeleon, got %v, %v", charmander.Species.Name, err)
+	}
+}
@@ -0,0 +1,104 @@
+package pokemon
+
+// EvolutionTrigger is what caused an evolution check.
+type EvolutionTrigger int
+
+const (
+	LevelUpTrigger EvolutionTrigger = iota
+	ItemTrigger
+	TradeTrigger
+)
+
+func (t EvolutionTrigger) String() string {
+	return [...]string{"LevelUp", "Item", "Trade"}[t]
+}
+
+// EvolutionContext is everything an EvolutionMethod gets to look at.
+type EvolutionContext struct {
+	Trigger  EvolutionTrigger
+	Time     Time
+	Weather  Weather
+	Location string
+	Item     Item // the item being used, for ItemTrigger
+
+	Party      []*Pokemon // the rest of the owner's party
+	TradedWith *Species   // what the Pokemon was traded for, for TradeTrigger
+}
+
+// EvolutionEvent describes an evolution that happened, or was cancelled.
+type EvolutionEvent struct {
+	Pokemon      *Pokemon
+	From         *Species
+	Into         *Species
+	Trigger      EvolutionTrigger
+	ConsumedItem Item // held or used item the evolution took, if any
+	Cancelled    bool
+}
+
+// EvolutionEngine decides when Pokemon evolve. It is consulted after levelling up,
+// using an item, trading and battles.
+type EvolutionEngine struct {
+	Pokedex PokedexRepository
+	// Confirm is asked before every evolution, returning false cancels it. A nil Confirm always evolves.
+	Confirm func(p *Pokemon, into *Species) bool
+}
+
+func NewEvolutionEngine(pokedex PokedexRepository) *EvolutionEngine {
+	return &EvolutionEngine{Pokedex: pokedex}
+}
+
+// Check evolves p if it can under ctx. It returns nil when no evolution applies.
+func (e *EvolutionEngine) Check(p *Pokemon, ctx EvolutionContext) (*EvolutionEvent, error) {
+	stage, newSpecies, err := p.findEvolution(e.Pokedex, ctx)
+	if err != nil || newSpecies == nil {
+		return nil, err
+	}
+
+	if e.Confirm != nil && !e.Confirm(p, newSpecies) {
+		return &EvolutionEvent{Pokemon: p, From: p.Species, Into: newSpecies, Trigger: ctx.Trigger, Cancelled: true}, nil
+	}
+	return p.evolveInto(stage, newSpecies, ctx), nil
+}
+
+func (e *EvolutionEngine) AfterLevelUp(p *Pokemon, ctx EvolutionContext) (*EvolutionEvent, error) {
+	ctx.Trigger = LevelUpTrigger
+	return e.Check(p, ctx)
+}
+
+func (e *EvolutionEngine) AfterItem(p *Pokemon, item Item, ctx EvolutionContext) (*EvolutionEvent, error) {
+	ctx.Trigger = ItemTrigger
+	ctx.Item = item
+	return e.Check(p, ctx)
+}
+
+func (e *EvolutionEngine) AfterTrade(p *Pokemon, ctx EvolutionContext) (*EvolutionEvent, error) {
+	ctx.Trigger = TradeTrigger
+	return e.Check(p, ctx)
+}
+
+// Trade swaps the two Pokemon between trainers and checks both for trade evolutions.
+func (e *EvolutionEngine) Trade(t1 *Trainer, p1 *Pokemon, t2 *Trainer, p2 *Pokemon, ctx EvolutionContext) ([]EvolutionEvent, error) {
+	if err := t1.Trade(p1, t2, p2); err != nil {
+		return nil, err
+	}
+
+	// Each side was traded for the other's species as it was before either evolved.
+	var events []EvolutionEvent
+	trades := []struct {
+		pokemon    *Pokemon
+		tradedWith *Species
+		owner      *Trainer
+	}{{p1, p2.Species, t2}, {p2, p1.Species, t1}}
+	for _, trade := range trades {
+		ctx.TradedWith = trade.tradedWith
+		ctx.Party = trade.owner.Team[:]
+		event, err := e.AfterTrade(trade.pokemon, ctx)
+		if err != nil {
+			return events, err
+		}
+		if event != nil {
+			events = append(events, *event)
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,183 @@
+package pokemon
+
+import "testing"
+
+func newEvolutionTestPokedex() *Pokedex {
+	return NewPokedex([]Species{
+		{ID: 4, Name: "Charmander", BaseStats: Stats{HP: 39, Attack: 52, Defense: 43, SpecialAttack: 60, SpecialDefense: 50, Speed: 65},
+			EvolutionStages: []EvolutionStage{NewEvolutionStage(5, LevelEvolution{RequiredLevel: 16})}},
+		{ID: 5, Name: "Charmeleon", BaseStats: Stats{HP: 58, Attack: 64, Defense: 58, SpecialAttack: 80, SpecialDefense: 65, Speed: 80}},
+		{ID: 64, Name: "Kadabra", BaseStats: Stats{HP: 40, Attack: 35, Defense: 30, SpecialAttack: 120, SpecialDefense: 70, Speed: 105},
+			EvolutionStages: []EvolutionStage{NewEvolutionStage(65, tradeOnly{})}},
+		{ID: 65, Name: "Alakazam", BaseStats: Stats{HP: 55, Attack: 50, Defense: 45, SpecialAttack: 135, SpecialDefense: 95, Speed: 120}},
+	})
+}
+
+// tradeOnly stands in for a trade evolution method.
+type tradeOnly struct{}
+
+func (tradeOnly) CanEvolve(_ *Pokemon, ctx EvolutionContext) bool {
+	return ctx.Trigger == TradeTrigger
+}
+
+func TestEvolutionEngineAfterLevelUp(t *testing.T) {
+	pokedex := newEvolutionTestPokedex()
+	engine := NewEvolutionEngine(pokedex)
+	charmander := NewPokemon(pokedex.GetSpeciesByID(4), 15, nil, nil, [4]Move{})
+
+	event, err := engine.AfterLevelUp(charmander, EvolutionContext{})
+	if err != nil || event != nil {
+		t.Fatalf("AfterLevelUp() at level 15 = %v, %v, want nothing", event, err)
+	}
+
+	charmander.LevelUp()
+	event, err = engine.AfterLevelUp(charmander, EvolutionContext{})
+	if err != nil {
+		t.Fatalf("AfterLevelUp() error %v", err)
+	}
+	if event == nil || event.From.Name != "Charmander" || event.Into.Name != "Charmeleon" || event.Trigger != LevelUpTrigger {
+		t.Fatalf("AfterLevelUp() event = %+v, want Charmander into Charmeleon", event)
+	}
+	if charmander.Species.ID != 5 {
+		t.Errorf("Expected species to be Charmeleon, got %v", charmander.Species.Name)
+	}
+
+	// Level evolutions don't happen from items or trades
+	charmander = NewPokemon(pokedex.GetSpeciesByID(4), 20, nil, nil, [4]Move{})
+	if event, _ := engine.AfterItem(charmander, OranBerry, EvolutionContext{}); event != nil {
+		t.Errorf("AfterItem() evolved through a level evolution")
+	}
+}
+
+func TestEvolutionEngineCancel(t *testing.T) {
+	pokedex := newEvolutionTestPokedex()
+	engine := NewEvolutionEngine(pokedex)
+	engine.Confirm = func(p *Pokemon, into *Species) bool { return false }
+	charmander := NewPokemon(pokedex.GetSpeciesByID(4), 16, nil, nil, [4]Move{})
+	stats := charmander.Stats
+
+	event, err := engine.AfterLevelUp(charmander, EvolutionContext{})
+	if err != nil || event == nil || !event.Cancelled {
+		t.Fatalf("AfterLevelUp() = %+v, %v, want a cancelled evolution", event, err)
+	}
+	if charmander.Species.ID != 4 || charmander.Stats != stats {
+		t.Errorf("Cancelled evolution still changed the Pokemon")
+	}
+}
+
+func TestEvolutionEngineTrade(t *testing.T) {
+	pokedex := newEvolutionTestPokedex()
+	engine := NewEvolutionEngine(pokedex)
+	kadabra := NewPokemon(pokedex.GetSpeciesByID(64), 30, nil, nil, [4]Move{})
+	charmander := NewPokemon(pokedex.GetSpeciesByID(4), 10, nil, nil, [4]Move{})
+	ash := NewTrainer("Ash", [6]*Pokemon{charmander})
+	gary := NewTrainer("Gary", [6]*Pokemon{nil, kadabra})
+
+	events, err := engine.Trade(ash, charmander, gary, kadabra, EvolutionContext{})
+	if err != nil {
+		t.Fatalf("Trade() error %v", err)
+	}
+
+	if ash.Team[0] != kadabra || gary.Team[1] != charmander {
+		t.Errorf("Trade() did not swap the Pokemon")
+	}
+	if len(events) != 1 || events[0].Into.Name != "Alakazam" || kadabra.Species.ID != 65 {
+		t.Errorf("Trade() events = %+v, want Kadabra into Alakazam", events)
+	}
+
+	if _, err := engine.Trade(ash, charmander, gary, kadabra, EvolutionContext{}); err == nil {
+		t.Errorf("Expected error trading Pokemon that aren't on the trainers' teams")
+	}
+}
+
+func TestEvolutionEngineTradeForEachOther(t *testing.T) {
+	pokedex := NewPokedex([]Species{
+		{ID: 588, Name: "Karrablast", EvolutionStages: []EvolutionStage{NewEvolutionStage(589, TradeEvolution{TradedWith: 616})}},
+		{ID: 589, Name: "Escavalier"},
+		{ID: 616, Name: "Shelmet", EvolutionStages: []EvolutionStage{NewEvolutionStage(617, TradeEvolution{TradedWith: 588})}},
+		{ID: 617, Name: "Accelgor"},
+	})
+	engine := NewEvolutionEngine(pokedex)
+	karrablast := NewPokemon(pokedex.GetSpeciesByID(588), 30, nil, nil, [4]Move{})
+	shelmet := NewPokemon(pokedex.GetSpeciesByID(616), 30, nil, nil, [4]Move{})
+	ash := NewTrainer("Ash", [6]*Pokemon{karrablast})
+	gary := NewTrainer("Gary", [6]*Pokemon{shelmet})
+
+	events, err := engine.Trade(ash, karrablast, gary, shelmet, EvolutionContext{})
+	if err != nil {
+		t.Fatalf("Trade() error %v", err)
+	}
+	if len(events) != 2 || karrablast.Species.Name != "Escavalier" || shelmet.Species.Name != "Accelgor" {
+		t.Errorf("Trade() events = %+v, want both Karrablast and Shelmet to evolve", events)
+	}
+}
+
+func TestBattleEvolvesAfterwards(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	pokedex := newEvolutionTestPokedex()
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 50, PP: 35, Accuracy: 100}
+	charmander := NewPokemon(pokedex.GetSpeciesByID(4), 15, nil, nil, [4]Move{tackle})
+	wild := &Pokemon{Species: &Species{Name: "Caterpie", BaseExpYield: 200}, Level: 30, Health: Health{Current: 1, Max: 1}, Moves: NewMoveSlots([4]Move{tackle})}
+
+	battle := NewBattle(&MockBattler{Pokemon: charmander, Action: BattleAction{Type: Attack, Move: tackle}}, &WildPokemon{Pokemon: wild})
+	battle.Evolutions = NewEvolutionEngine(pokedex)
+	battle.Run()
+
+	if charmander.Level < 16 {
+		t.Fatalf("Expected Charmander to reach level 16, got %d", charmander.Level)
+	}
+	if len(battle.EvolutionEvents) != 1 || charmander.Species.Name != "Charmeleon" {
+		t.Errorf("Expected Charmander to evolve after the battle, events %+v", battle.EvolutionEvents)
+	}
+}
+
+func TestBattleLogsFailedEvolution(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	// Charmeleon is missing from the Pokedex
+	pokedex := NewPokedex([]Species{
+		{ID: 4, Name: "Charmander", BaseStats: Stats{HP: 39, Attack: 52, Defense: 43, SpecialAttack: 60, SpecialDefense: 50, Speed: 65},
+			EvolutionStages: []EvolutionStage{NewEvolutionStage(5, LevelEvolution{RequiredLevel: 16})}},
+	})
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 50, PP: 35, Accuracy: 100}
+	charmander := NewPokemon(pokedex.GetSpeciesByID(4), 15, nil, nil, [4]Move{tackle})
+	wild := &Pokemon{Species: &Species{Name: "Caterpie", BaseExpYield: 200}, Level: 30, Health: Health{Current: 1, Max: 1}, Moves: NewMoveSlots([4]Move{tackle})}
+
+	battle := NewBattle(&MockBattler{Pokemon: charmander, Action: BattleAction{Type: Attack, Move: tackle}}, &WildPokemon{Pokemon: wild})
+	battle.Evolutions = NewEvolutionEngine(pokedex)
+	battle.Run()
+
+	if len(battle.EvolutionEvents) != 0 || charmander.Species.Name != "Charmander" {
+		t.Fatalf("Expected Charmander not to evolve, events %+v", battle.EvolutionEvents)
+	}
+	want := "Charmander couldn't evolve: Species with ID 5 does not exist"
+	for _, message := range battle.Log.Messages {
+		if message == want {
+			return
+		}
+	}
+	t.Errorf("log = %q, want %q", battle.Log.Messages, want)
+}
+
+func TestBattleEvolutionSeesParty(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	pokedex := NewPokedex([]Species{
+		{ID: 223, Name: "Remoraid"},
+		{ID: 226, Name: "Mantine"},
+		{ID: 458, Name: "Mantyke", EvolutionStages: []EvolutionStage{NewEvolutionStage(226, PartySpeciesEvolution{SpeciesID: 223})}},
+	})
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 50, PP: 35, Accuracy: 100}
+	mantyke := NewPokemon(pokedex.GetSpeciesByID(458), 15, nil, nil, [4]Move{tackle})
+	remoraid := NewPokemon(pokedex.GetSpeciesByID(223), 15, nil, nil, [4]Move{tackle})
+	wild := &Pokemon{Species: &Species{Name: "Caterpie", BaseExpYield: 200}, Level: 30, Health: Health{Current: 1, Max: 1}, Moves: NewMoveSlots([4]Move{tackle})}
+
+	battle := NewBattle(NewTrainer("Ash", [6]*Pokemon{mantyke, remoraid}), &WildPokemon{Pokemon: wild})
+	battle.Evolutions = NewEvolutionEngine(pokedex)
+	battle.Run()
+
+	if len(battle.EvolutionEvents) != 1 || mantyke.Species.Name != "Mantine" {
+		t.Errorf("Expected Mantyke to evolve with Remoraid in the party, events %+v", battle.EvolutionEvents)
+	}
+}
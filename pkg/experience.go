@@ -0,0 +1,152 @@
+package pokemon
+
+import (
+	"fmt"
+	"strings"
+)
+
+const MaxLevel = 100
+
+// GrowthRate is the curve that decides how much experience a species needs per level.
+type GrowthRate int
+
+const (
+	MediumFast GrowthRate = iota
+	Erratic
+	Fast
+	MediumSlow
+	Slow
+	Fluctuating
+)
+
+var growthRateNames = [...]string{"Medium Fast", "Erratic", "Fast", "Medium Slow", "Slow", "Fluctuating"}
+
+func (g GrowthRate) valid() bool {
+	return g >= 0 && int(g) < len(growthRateNames)
+}
+
+func (g GrowthRate) String() string {
+	if !g.valid() {
+		return fmt.Sprintf("GrowthRate(%d)", int(g))
+	}
+	return growthRateNames[g]
+}
+
+func (g GrowthRate) MarshalText() ([]byte, error) {
+	if !g.valid() {
+		return nil, fmt.Errorf("invalid growth rate %d", int(g))
+	}
+	return []byte(g.String()), nil
+}
+
+func (g *GrowthRate) UnmarshalText(text []byte) error {
+	for i, name := range growthRateNames {
+		if strings.EqualFold(name, string(text)) {
+			*g = GrowthRate(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid growth rate %q", text)
+}
+
+// ExperienceForLevel returns the total experience needed to reach level.
+func (g GrowthRate) ExperienceForLevel(level int) int {
+	if level <= 1 {
+		return 0
+	}
+	n := level
+	cube := n * n * n
+
+	switch g {
+	case Erratic:
+		switch {
+		case n < 50:
+			return cube * (100 - n) / 50
+		case n < 68:
+			return cube * (150 - n) / 100
+		case n < 98:
+			return cube * ((1911 - 10*n) / 3) / 500
+		default:
+			return cube * (160 - n) / 100
+		}
+	case Fast:
+		return 4 * cube / 5
+	case MediumSlow:
+		return 6*cube/5 - 15*n*n + 100*n - 140
+	case Slow:
+		return 5 * cube / 4
+	case Fluctuating:
+		switch {
+		case n < 15:
+			return cube * ((n+1)/3 + 24) / 50
+		case n < 36:
+			return cube * (n + 14) / 50
+		default:
+			return cube * (n/2 + 32) / 50
+		}
+	default:
+		return cube
+	}
+}
+
+// GainExperience adds experience and levels up as many times as it covers,
+// returning what happened on each level.
+func (p *Pokemon) GainExperience(amount int) []LevelUpResult {
+	p.Experience += amount
+
+	var levels []LevelUpResult
+	for p.Level < MaxLevel && p.Experience >= p.Species.GrowthRate.ExperienceForLevel(p.Level+1) {
+		levels = append(levels, p.LevelUp())
+	}
+	return levels
+}
+
+const (
+	trainerBattleExpMultiplier = 1.5
+	luckyEggExpMultiplier      = 1.5
+)
+
+// ExperienceYield is how much experience one of participants Pokemon gets for defeating fainted.
+func ExperienceYield(fainted *Pokemon, participants int, trainerBattle bool, holder *Pokemon) int {
+	if participants < 1 {
+		return 0
+	}
+
+	exp := float64(fainted.Species.BaseExpYield*fainted.Level) / 7
+	if trainerBattle {
+		exp *= trainerBattleExpMultiplier
+	}
+	exp /= float64(participants)
+	if holder != nil && holder.HasItem(LuckyEgg) {
+		exp *= luckyEggExpMultiplier
+	}
+
+	if exp < 1 {
+		return 1
+	}
+	return int(exp)
+}
+
+// ExperienceGain is what one Pokemon got out of AwardExperience.
+type ExperienceGain struct {
+	Experience int
+	LevelUps   []LevelUpResult
+}
+
+// AwardExperience splits the experience for defeating fainted between the Pokemon that
+// fought it and haven't fainted themselves. Returns what each of them gained.
+func AwardExperience(fainted *Pokemon, participants []*Pokemon, trainerBattle bool) map[*Pokemon]ExperienceGain {
+	var eligible []*Pokemon
+	for _, p := range participants {
+		if p != nil && !p.Health.IsFainted() {
+			eligible = append(eligible, p)
+		}
+	}
+
+	gained := make(map[*Pokemon]ExperienceGain)
+	for _, p := range eligible {
+		exp := ExperienceYield(fainted, len(eligible), trainerBattle, p)
+		gained[p] = ExperienceGain{Experience: exp, LevelUps: p.GainExperience(exp)}
+	}
+	return gained
+}
@@ -0,0 +1,138 @@
+package pokemon
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExperienceForLevel(t *testing.T) {
+	tests := []struct {
+		rate  GrowthRate
+		level int
+		want  int
+	}{
+		{MediumFast, 1, 0},
+		{MediumFast, 100, 1000000},
+		{Erratic, 50, 125000},
+		{Erratic, 100, 600000},
+		{Fast, 100, 800000},
+		{MediumSlow, 2, 9},
+		{MediumSlow, 50, 117360},
+		{MediumSlow, 100, 1059860},
+		{Slow, 100, 1250000},
+		{Fluctuating, 36, 46656},
+		{Fluctuating, 100, 1640000},
+	}
+
+	for _, tt := range tests {
+		if got := tt.rate.ExperienceForLevel(tt.level); got != tt.want {
+			t.Errorf("%v.ExperienceForLevel(%d) = %d, want %d", tt.rate, tt.level, got, tt.want)
+		}
+	}
+
+	for _, rate := range []GrowthRate{MediumFast, Erratic, Fast, MediumSlow, Slow, Fluctuating} {
+		for level := 2; level <= MaxLevel; level++ {
+			if rate.ExperienceForLevel(level) <= rate.ExperienceForLevel(level-1) {
+				t.Errorf("%v.ExperienceForLevel(%d) does not increase", rate, level)
+			}
+		}
+	}
+}
+
+func TestGrowthRateJSON(t *testing.T) {
+	data, err := json.Marshal(Species{Name: "Magikarp", GrowthRate: Slow})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded struct{ GrowthRate string }
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.GrowthRate != "Slow" {
+		t.Errorf("json.Marshal() GrowthRate = %q, %v, want \"Slow\"", decoded.GrowthRate, err)
+	}
+
+	var rate GrowthRate
+	if err := json.Unmarshal([]byte(`"medium slow"`), &rate); err != nil || rate != MediumSlow {
+		t.Errorf("json.Unmarshal() = %v, %v, want Medium Slow", rate, err)
+	}
+	if err := json.Unmarshal([]byte(`4`), &rate); err == nil {
+		t.Errorf("Expected error when unmarshalling a numeric growth rate, got nil")
+	}
+}
+
+func TestGainExperience(t *testing.T) {
+	species := &Species{Name: "Rattata", GrowthRate: MediumFast, BaseStats: Stats{HP: 30, Attack: 56, Defense: 35, SpecialAttack: 25, SpecialDefense: 35, Speed: 72}}
+	rattata := NewPokemon(species, 5, nil, nil, [4]Move{})
+
+	if rattata.Experience != 125 {
+		t.Fatalf("NewPokemon() experience = %d, want 125", rattata.Experience)
+	}
+
+	// 125 + 400 = 525, past level 8 (512) but short of level 9 (729)
+	if levels := rattata.GainExperience(400); len(levels) != 3 || rattata.Level != 8 {
+		t.Errorf("GainExperience() gained %d levels to %d, want 3 to 8", len(levels), rattata.Level)
+	}
+
+	if levels := rattata.GainExperience(10); len(levels) != 0 || rattata.Level != 8 {
+		t.Errorf("GainExperience() gained %d levels to %d, want none", len(levels), rattata.Level)
+	}
+
+	rattata.GainExperience(5000000)
+	if rattata.Level != MaxLevel {
+		t.Errorf("GainExperience() levelled to %d, want to stop at %d", rattata.Level, MaxLevel)
+	}
+}
+
+func TestAwardExperience(t *testing.T) {
+	faintedSpecies := &Species{Name: "Bulbasaur", BaseExpYield: 64}
+	fainted := &Pokemon{Species: faintedSpecies, Level: 10}
+	species := &Species{Name: "Pidgey", GrowthRate: MediumSlow, BaseStats: Stats{HP: 40, Attack: 45, Defense: 40, SpecialAttack: 35, SpecialDefense: 35, Speed: 56}}
+
+	tests := []struct {
+		name          string
+		participants  int
+		trainerBattle bool
+		luckyEgg      bool
+		want          int
+	}{
+		{"wild", 1, false, false, 91},
+		{"trainer", 1, true, false, 137},
+		{"split", 2, false, false, 45},
+		{"lucky egg", 1, false, true, 137},
+		{"trainer with lucky egg", 1, true, true, 205},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var participants []*Pokemon
+			for i := 0; i < tt.participants; i++ {
+				participants = append(participants, NewPokemon(species, 50, nil, nil, [4]Move{}))
+			}
+			if tt.luckyEgg {
+				participants[0].HeldItem = LuckyEgg
+			}
+			before := participants[0].Experience
+
+			gained := AwardExperience(fainted, participants, tt.trainerBattle)
+
+			if gained[participants[0]].Experience != tt.want || participants[0].Experience != before+tt.want {
+				t.Errorf("AwardExperience() gained %d, want %d", gained[participants[0]].Experience, tt.want)
+			}
+		})
+	}
+}
+
+func TestAwardExperienceSkipsFainted(t *testing.T) {
+	fainted := &Pokemon{Species: &Species{BaseExpYield: 64}, Level: 10}
+	species := &Species{Name: "Pidgey", BaseStats: Stats{HP: 40}}
+	standing := NewPokemon(species, 50, nil, nil, [4]Move{})
+	down := NewPokemon(species, 50, nil, nil, [4]Move{})
+	down.TakeDamage(down.Health.Max)
+
+	gained := AwardExperience(fainted, []*Pokemon{standing, down}, false)
+
+	if gained[standing].Experience != 91 {
+		t.Errorf("AwardExperience() gave %d to the standing Pokemon, want the full 91", gained[standing].Experience)
+	}
+	if _, ok := gained[down]; ok {
+		t.Errorf("AwardExperience() should not give experience to a fainted Pokemon")
+	}
+}
@@ -2,12 +2,58 @@ package pokemon
 
 type ItemEffect func(*Pokemon)
 
+// ItemID identifies an item independently of the value holding it.
+type ItemID string
+
 type Item interface {
 	Use(p *Pokemon)
 	Name() string
+	ID() ItemID
+}
+
+var itemRegistry = make(map[ItemID]Item)
+
+// RegisterItem makes item available to ItemByID, which is how items are found again
+// when loading data that refers to them.
+func RegisterItem(item Item) {
+	itemRegistry[item.ID()] = item
+}
+
+func ItemByID(id ItemID) (Item, bool) {
+	item, exists := itemRegistry[id]
+	return item, exists
+}
+
+func init() {
+	for _, item := range []Item{
+		OranBerry, LuckyEgg, MetalCoat, KingsRock, RazorClaw,
+		RazorFang, OvalStone, DragonScale, UpGrade, DubiousDisc, Protector, Electirizer,
+		Magmarizer, ReaperCloth, PrismScale, DeepSeaTooth, DeepSeaScale, Sachet, WhippedDream,
+		FireStone, WaterStone, ThunderStone, LeafStone, MoonStone, DawnStone,
+		SunStone, ShinyStone, DuskStone, IceStone,
+		HPUp, Protein, Iron, Calcium, Zinc, Carbos,
+		PomegBerry, KelpsyBerry, QualotBerry, HondewBerry, GrepaBerry, TamatoBerry,
+		Ether, MaxEther, Elixir, MaxElixir, PPUp, PPMax,
+		PokeBall, GreatBall, UltraBall, MasterBall,
+	} {
+		RegisterItem(item)
+	}
+}
+
+// SameItem reports whether a and b are the same item. Items without an ID are
+// only the same as themselves.
+func SameItem(a, b Item) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.ID() == "" || b.ID() == "" {
+		return a == b
+	}
+	return a.ID() == b.ID()
 }
 
 type Berry struct {
+	id         ItemID
 	name       string
 	isConsumed bool
 	effect     func(p *Pokemon)
@@ -24,8 +70,13 @@ func (b *Berry) Name() string {
 	return b.name
 }
 
+func (b *Berry) ID() ItemID {
+	return b.id
+}
+
 // example item
 var OranBerry = &Berry{
+	id:   "oran-berry",
 	name: "Oran Berry",
 	effect: func(p *Pokemon) {
 		if p.Health.Current < p.Health.Max {
@@ -35,6 +86,74 @@ var OranBerry = &Berry{
 	isConsumed: true,
 }
 
+// HoldItem does nothing when used, it only matters while a Pokemon holds it.
+type HoldItem struct {
+	id   ItemID
+	name string
+}
+
+func (h *HoldItem) Use(p *Pokemon) {}
+
+func (h *HoldItem) Name() string {
+	return h.name
+}
+
+func (h *HoldItem) ID() ItemID {
+	return h.id
+}
+
+var (
+	LuckyEgg  = &HoldItem{id: "lucky-egg", name: "Lucky Egg"}
+	MetalCoat = &HoldItem{id: "metal-coat", name: "Metal Coat"}
+	KingsRock = &HoldItem{id: "kings-rock", name: "King's Rock"}
+	RazorClaw = &HoldItem{id: "razor-claw", name: "Razor Claw"}
+
+	// held while levelling up or trading to evolve
+	RazorFang    = &HoldItem{id: "razor-fang", name: "Razor Fang"}
+	OvalStone    = &HoldItem{id: "oval-stone", name: "Oval Stone"}
+	DragonScale  = &HoldItem{id: "dragon-scale", name: "Dragon Scale"}
+	UpGrade      = &HoldItem{id: "up-grade", name: "Up-Grade"}
+	DubiousDisc  = &HoldItem{id: "dubious-disc", name: "Dubious Disc"}
+	Protector    = &HoldItem{id: "protector", name: "Protector"}
+	Electirizer  = &HoldItem{id: "electirizer", name: "Electirizer"}
+	Magmarizer   = &HoldItem{id: "magmarizer", name: "Magmarizer"}
+	ReaperCloth  = &HoldItem{id: "reaper-cloth", name: "Reaper Cloth"}
+	PrismScale   = &HoldItem{id: "prism-scale", name: "Prism Scale"}
+	DeepSeaTooth = &HoldItem{id: "deep-sea-tooth", name: "Deep Sea Tooth"}
+	DeepSeaScale = &HoldItem{id: "deep-sea-scale", name: "Deep Sea Scale"}
+	Sachet       = &HoldItem{id: "sachet", name: "Sachet"}
+	WhippedDream = &HoldItem{id: "whipped-dream", name: "Whipped Dream"}
+)
+
+// EvolutionItem is used from the bag to evolve a Pokemon, see EvolutionEngine.AfterItem.
+type EvolutionItem struct {
+	id   ItemID
+	name string
+}
+
+func (e *EvolutionItem) Use(p *Pokemon) {}
+
+func (e *EvolutionItem) Name() string {
+	return e.name
+}
+
+func (e *EvolutionItem) ID() ItemID {
+	return e.id
+}
+
+var (
+	FireStone    = &EvolutionItem{id: "fire-stone", name: "Fire Stone"}
+	WaterStone   = &EvolutionItem{id: "water-stone", name: "Water Stone"}
+	ThunderStone = &EvolutionItem{id: "thunder-stone", name: "Thunder Stone"}
+	LeafStone    = &EvolutionItem{id: "leaf-stone", name: "Leaf Stone"}
+	MoonStone    = &EvolutionItem{id: "moon-stone", name: "Moon Stone"}
+	DawnStone    = &EvolutionItem{id: "dawn-stone", name: "Dawn Stone"}
+	SunStone     = &EvolutionItem{id: "sun-stone", name: "Sun Stone"}
+	ShinyStone   = &EvolutionItem{id: "shiny-stone", name: "Shiny Stone"}
+	DuskStone    = &EvolutionItem{id: "dusk-stone", name: "Dusk Stone"}
+	IceStone     = &EvolutionItem{id: "ice-stone", name: "Ice Stone"}
+)
+
 type ReviveItem interface {
 	Item
 	Revive(p *Pokemon)
@@ -51,3 +170,176 @@ func (r *Revive) Revive(p *Pokemon) {
 		p.Health.increase(int(p.Health.Max / 2))
 	}
 }
+
+// MoveItem is used on one of a Pokemon's moves, like an Ether or a PP Up.
+type MoveItem interface {
+	Item
+	UseOnMove(p *Pokemon, slot int) bool
+}
+
+// PPRestore gives back PP. Ethers restore one move and Elixirs every move, an
+// amount of 0 restores all of it.
+type PPRestore struct {
+	id       ItemID
+	name     string
+	amount   int
+	allMoves bool
+}
+
+// Use restores every move for an Elixir. An Ether goes to the move missing the most PP.
+func (r *PPRestore) Use(p *Pokemon) {
+	r.UseOnMove(p, mostMissingPP(p))
+}
+
+// UseOnMove restores the move in slot, or every move for an Elixir. It returns false
+// if no PP was restored.
+func (r *PPRestore) UseOnMove(p *Pokemon, slot int) bool {
+	if r.allMoves {
+		restored := false
+		for i := range p.Moves {
+			restored = r.restore(&p.Moves[i]) || restored
+		}
+		return restored
+	}
+	if slot < 0 || slot >= len(p.Moves) {
+		return false
+	}
+	return r.restore(&p.Moves[slot])
+}
+
+func (r *PPRestore) restore(slot *MoveSlot) bool {
+	amount := r.amount
+	if amount == 0 {
+		amount = slot.MaxPP()
+	}
+	return slot.RestorePP(amount) > 0
+}
+
+func (r *PPRestore) Name() string {
+	return r.name
+}
+
+func (r *PPRestore) ID() ItemID {
+	return r.id
+}
+
+func mostMissingPP(p *Pokemon) int {
+	best, missing := 0, 0
+	for i := range p.Moves {
+		if m := p.Moves[i].MaxPP() - p.Moves[i].PP; !p.Moves[i].Empty() && m > missing {
+			best, missing = i, m
+		}
+	}
+	return best
+}
+
+var (
+	Ether     = &PPRestore{id: "ether", name: "Ether", amount: 10}
+	MaxEther  = &PPRestore{id: "max-ether", name: "Max Ether"}
+	Elixir    = &PPRestore{id: "elixir", name: "Elixir", amount: 10, allMoves: true}
+	MaxElixir = &PPRestore{id: "max-elixir", name: "Max Elixir", allMoves: true}
+)
+
+// PPBoost raises a move's maximum PP, a PP Up by one step and a PP Max all the way.
+type PPBoost struct {
+	id   ItemID
+	name string
+	ups  int
+}
+
+// Use boosts the first move that can still take it.
+func (b *PPBoost) Use(p *Pokemon) {
+	for i := range p.Moves {
+		if b.UseOnMove(p, i) {
+			return
+		}
+	}
+}
+
+func (b *PPBoost) UseOnMove(p *Pokemon, slot int) bool {
+	if slot < 0 || slot >= len(p.Moves) {
+		return false
+	}
+	return p.Moves[slot].AddPPUps(b.ups)
+}
+
+func (b *PPBoost) Name() string {
+	return b.name
+}
+
+func (b *PPBoost) ID() ItemID {
+	return b.id
+}
+
+var (
+	PPUp  = &PPBoost{id: "pp-up", name: "PP Up", ups: 1}
+	PPMax = &PPBoost{id: "pp-max", name: "PP Max", ups: maxPPUps}
+)
+
+const evItemAmount = 10
+
+// Vitamin raises one stat's effort values, like Protein or Carbos.
+type Vitamin struct {
+	id   ItemID
+	name string
+	stat Stat
+}
+
+func (v *Vitamin) Use(p *Pokemon) {
+	p.AddEVs(singleStat(v.stat, evItemAmount))
+}
+
+func (v *Vitamin) Name() string {
+	return v.name
+}
+
+func (v *Vitamin) ID() ItemID {
+	return v.id
+}
+
+var (
+	HPUp    = &Vitamin{id: "hp-up", name: "HP Up", stat: StatHP}
+	Protein = &Vitamin{id: "protein", name: "Protein", stat: StatAttack}
+	Iron    = &Vitamin{id: "iron", name: "Iron", stat: StatDefense}
+	Calcium = &Vitamin{id: "calcium", name: "Calcium", stat: StatSpecialAttack}
+	Zinc    = &Vitamin{id: "zinc", name: "Zinc", stat: StatSpecialDefense}
+	Carbos  = &Vitamin{id: "carbos", name: "Carbos", stat: StatSpeed}
+)
+
+// EVBerry lowers one stat's effort values and makes the Pokemon a bit friendlier,
+// but only if there were effort values to lower.
+type EVBerry struct {
+	id   ItemID
+	name string
+	stat Stat
+}
+
+func (b *EVBerry) Use(p *Pokemon) {
+	if p.AddEVs(singleStat(b.stat, -evItemAmount)) == (Stats{}) {
+		return
+	}
+	p.Friendship = min(p.Friendship+10, MaxFriendship)
+}
+
+func (b *EVBerry) Name() string {
+	return b.name
+}
+
+func (b *EVBerry) ID() ItemID {
+	return b.id
+}
+
+var (
+	PomegBerry  = &EVBerry{id: "pomeg-berry", name: "Pomeg Berry", stat: StatHP}
+	KelpsyBerry = &EVBerry{id: "kelpsy-berry", name: "Kelpsy Berry", stat: StatAttack}
+	QualotBerry = &EVBerry{id: "qualot-berry", name: "Qualot Berry", stat: StatDefense}
+	HondewBerry = &EVBerry{id: "hondew-berry", name: "Hondew Berry", stat: StatSpecialAttack}
+	GrepaBerry  = &EVBerry{id: "grepa-berry", name: "Grepa Berry", stat: StatSpecialDefense}
+	TamatoBerry = &EVBerry{id: "tamato-berry", name: "Tamato Berry", stat: StatSpeed}
+)
+
+func singleStat(stat Stat, value int) Stats {
+	var s Stats
+	s.Set(stat, value)
+	return s
+}
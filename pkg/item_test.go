@@ -16,3 +16,118 @@ func TestHeldItem(t *testing.T) {
 		t.Errorf("Expected health to be 90, not %d", pokemon.Health.Current)
 	}
 }
+
+func TestVitamins(t *testing.T) {
+	hardy := Hardy
+	species := &Species{BaseStats: Stats{HP: 50, Attack: 50, Defense: 50, SpecialAttack: 50, SpecialDefense: 50, Speed: 50}}
+	pokemon := NewPokemon(species, 100, nil, &hardy, [4]Move{})
+	speed := pokemon.Stats.Speed
+
+	for i := 0; i < 4; i++ {
+		Carbos.Use(pokemon)
+	}
+
+	if pokemon.EVs().Speed != 40 {
+		t.Errorf("Expected 40 Speed EVs after four Carbos, got %d", pokemon.EVs().Speed)
+	}
+	if pokemon.Stats.Speed != speed+10 {
+		t.Errorf("Expected Speed to go from %d to %d, got %d", speed, speed+10, pokemon.Stats.Speed)
+	}
+}
+
+func TestEVBerries(t *testing.T) {
+	species := &Species{BaseStats: Stats{HP: 50, Attack: 50, Defense: 50, SpecialAttack: 50, SpecialDefense: 50, Speed: 50}}
+	pokemon := NewPokemon(species, 100, nil, nil, [4]Move{})
+	pokemon.AddEVs(Stats{HP: 15})
+
+	PomegBerry.Use(pokemon)
+	if pokemon.EVs().HP != 5 || pokemon.Friendship != 10 {
+		t.Errorf("Expected 5 HP EVs and 10 friendship, got %d and %d", pokemon.EVs().HP, pokemon.Friendship)
+	}
+
+	PomegBerry.Use(pokemon)
+	if pokemon.EVs().HP != 0 {
+		t.Errorf("Expected HP EVs to stop at 0, got %d", pokemon.EVs().HP)
+	}
+
+	PomegBerry.Use(pokemon)
+	if pokemon.Friendship != 20 {
+		t.Errorf("Expected friendship to stay at 20 when there are no EVs to lower, got %d", pokemon.Friendship)
+	}
+}
+
+func TestSameItem(t *testing.T) {
+	copied := *OranBerry
+	if !SameItem(OranBerry, &copied) {
+		t.Errorf("Expected copies of the same item to compare equal")
+	}
+	if SameItem(OranBerry, LuckyEgg) || SameItem(OranBerry, nil) || !SameItem(nil, nil) {
+		t.Errorf("SameItem() compared items incorrectly")
+	}
+
+	unnamed, other := &HoldItem{name: "Mystery"}, &HoldItem{name: "Other"}
+	if SameItem(unnamed, other) || !SameItem(unnamed, unnamed) {
+		t.Errorf("Items without an ID should only match themselves")
+	}
+
+	pokemon := &Pokemon{HeldItem: &copied}
+	if !pokemon.HasItem(OranBerry) || pokemon.HasItem(MetalCoat) {
+		t.Errorf("HasItem() should match the held item only")
+	}
+	if (&Pokemon{}).HasItem(OranBerry) {
+		t.Errorf("HasItem() should be false when holding nothing")
+	}
+}
+
+func newPPTestPokemon() *Pokemon {
+	species := &Species{BaseStats: Stats{HP: 50}}
+	pokemon := NewPokemon(species, 10, nil, nil, [4]Move{{Name: "Tackle", PP: 35}, {Name: "Ember", PP: 25}})
+	pokemon.Moves[0].PP = 30
+	pokemon.Moves[1].PP = 5
+	return pokemon
+}
+
+func TestPPRestoreItems(t *testing.T) {
+	tests := []struct {
+		name string
+		use  func(p *Pokemon) bool
+		want [2]int
+	}{
+		{"Ether on a move", func(p *Pokemon) bool { return Ether.UseOnMove(p, 0) }, [2]int{35, 5}},
+		{"Ether picks the emptiest move", func(p *Pokemon) bool { Ether.Use(p); return true }, [2]int{30, 15}},
+		{"Max Ether", func(p *Pokemon) bool { return MaxEther.UseOnMove(p, 1) }, [2]int{30, 25}},
+		{"Elixir", func(p *Pokemon) bool { return Elixir.UseOnMove(p, 0) }, [2]int{35, 15}},
+		{"Max Elixir", func(p *Pokemon) bool { MaxElixir.Use(p); return true }, [2]int{35, 25}},
+		{"empty slot", func(p *Pokemon) bool { return !Ether.UseOnMove(p, 2) }, [2]int{30, 5}},
+		{"out of range", func(p *Pokemon) bool { return !MaxEther.UseOnMove(p, 4) }, [2]int{30, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pokemon := newPPTestPokemon()
+			if !tt.use(pokemon) {
+				t.Errorf("Unexpected result using the item")
+			}
+			if got := [2]int{pokemon.Moves[0].PP, pokemon.Moves[1].PP}; got != tt.want {
+				t.Errorf("PP = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPPBoostItems(t *testing.T) {
+	pokemon := newPPTestPokemon()
+
+	if !PPUp.UseOnMove(pokemon, 1) || pokemon.Moves[1].MaxPP() != 30 {
+		t.Errorf("PP Up should raise Ember to 30 max PP, got %d", pokemon.Moves[1].MaxPP())
+	}
+
+	PPMax.Use(pokemon)
+	if pokemon.Moves[0].MaxPP() != 56 {
+		t.Errorf("PP Max should raise Tackle to 56 max PP, got %d", pokemon.Moves[0].MaxPP())
+	}
+
+	if item, ok := ItemByID("max-elixir"); !ok || !SameItem(item, MaxElixir) {
+		t.Errorf("Max Elixir should be registered")
+	}
+}
@@ -0,0 +1,270 @@
+package pokemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EffectSpec declares one of a move's effects by kind, e.g.
+// {"kind": "stat_change", "params": {"stat": "Attack", "stages": 2, "target": "user"}}.
+type EffectSpec struct {
+	Kind   string          `json:"kind"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// MoveEffectFactory sets up a declared effect on m, usually by adding to m.Effects.
+type MoveEffectFactory func(m *Move, params json.RawMessage) error
+
+// moveEffects is filled in directly rather than from init so that the embedded
+// moves can load while package variables are initialized.
+var moveEffects = map[string]MoveEffectFactory{
+	"stat_change":   statChangeEffect,
+	"status":        statusEffect,
+	"recoil":        recoilEffect,
+	"recoil_max_hp": maxHPRecoilEffect,
+	"drain":         drainEffect,
+	"multi_hit":     multiHitEffect,
+	"substitute":    substituteEffect,
+}
+
+// RegisterMoveEffect makes kind usable in EffectSpecs.
+func RegisterMoveEffect(kind string, factory MoveEffectFactory) {
+	moveEffects[kind] = factory
+}
+
+// LoadEffects turns the move's EffectSpecs into its Effects, replacing any it had.
+func (m *Move) LoadEffects() error {
+	m.Effects = nil
+	m.MinHits, m.MaxHits = 0, 0
+	for _, spec := range m.EffectSpecs {
+		factory, ok := moveEffects[spec.Kind]
+		if !ok {
+			return fmt.Errorf("move %s: unknown effect %q", m.Name, spec.Kind)
+		}
+		if err := factory(m, spec.Params); err != nil {
+			return fmt.Errorf("move %s: effect %s: %w", m.Name, spec.Kind, err)
+		}
+	}
+	return nil
+}
+
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	return json.Unmarshal(params, v)
+}
+
+// rollChance reports whether an effect with a percentage chance happens, 0 is always.
+func rollChance(chance int) bool {
+	return chance <= 0 || chance >= 100 || rng.Intn(100) < chance
+}
+
+type statChangeParams struct {
+	Stat   string `json:"stat"`
+	Stages int    `json:"stages"`
+	Target string `json:"target"` // "user" or "target", the default
+	Chance int    `json:"chance"`
+}
+
+func statChangeEffect(m *Move, params json.RawMessage) error {
+	var p statChangeParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	stat, err := StringToStat(p.Stat)
+	if err != nil {
+		return err
+	}
+	if stat == StatHP {
+		return fmt.Errorf("HP has no stat stages")
+	}
+	onUser, err := effectTarget(p.Target)
+	if err != nil {
+		return err
+	}
+
+	m.Effects = append(m.Effects, func(user, target *Pokemon, result DamageResult) {
+		if (!onUser && result.Substitute) || !rollChance(p.Chance) {
+			return
+		}
+		if onUser {
+			user.Modifiers.ChangeStage(stat, p.Stages)
+		} else {
+			target.Modifiers.ChangeStage(stat, p.Stages)
+		}
+	})
+	return nil
+}
+
+func effectTarget(target string) (onUser bool, err error) {
+	switch target {
+	case "user":
+		return true, nil
+	case "target", "":
+		return false, nil
+	}
+	return false, fmt.Errorf("unknown effect target %q", target)
+}
+
+// statusConditions creates a fresh status for each infliction, by name, or nil when the
+// user can't inflict it on the target.
+var statusConditions = map[string]func(user, target *Pokemon) StatusEffect{
+	"poison":    func(_, _ *Pokemon) StatusEffect { return &PoisonStatus{} },
+	"toxic":     func(_, _ *Pokemon) StatusEffect { return &ToxicStatus{} },
+	"burn":      func(_, _ *Pokemon) StatusEffect { return &BurnStatus{} },
+	"paralysis": func(_, _ *Pokemon) StatusEffect { return &ParalysisStatus{} },
+	"freeze":    func(_, _ *Pokemon) StatusEffect { return &FreezeStatus{} },
+	"sleep":     func(_, _ *Pokemon) StatusEffect { return &SleepStatus{} },
+	"confusion": func(_, _ *Pokemon) StatusEffect { return &ConfusionStatus{} },
+	"flinch":    func(_, _ *Pokemon) StatusEffect { return &FlinchStatus{} },
+	"infatuation": func(user, target *Pokemon) StatusEffect {
+		if !canInfatuate(user, target) {
+			return nil
+		}
+		return &InfatuationStatus{Source: user}
+	},
+	"leech-seed": func(user, _ *Pokemon) StatusEffect { return &LeechSeedStatus{Recipient: user} },
+	"trap":       func(user, _ *Pokemon) StatusEffect { return &BoundStatus{Source: user} },
+	"taunt":      func(_, _ *Pokemon) StatusEffect { return &TauntStatus{} },
+	"encore": func(_, target *Pokemon) StatusEffect {
+		if target.lastMove == "" {
+			return nil
+		}
+		return &EncoreStatus{Move: target.lastMove}
+	},
+}
+
+// SecondaryEffect is a move's chance to inflict a status condition on its target. Its
+// Apply is an Effect.
+type SecondaryEffect struct {
+	Status string // a name from statusConditions
+	Chance int    // percent, 0 means always
+}
+
+// Apply rolls the chance and inflicts the status, unless the target can't take it, is
+// immune to the move's type or its substitute took the hit.
+func (e SecondaryEffect) Apply(user, target *Pokemon, result DamageResult) {
+	newStatus, ok := statusConditions[strings.ToLower(e.Status)]
+	if !ok || result.Substitute || result.Effectiveness == 0 || !rollChance(e.Chance) {
+		return
+	}
+	if status := newStatus(user, target); status != nil {
+		target.StatusManager.Inflict(target, status)
+	}
+}
+
+type statusParams struct {
+	Status string `json:"status"`
+	Chance int    `json:"chance"`
+}
+
+// statusEffect adds a SecondaryEffect inflicting the named status.
+func statusEffect(m *Move, params json.RawMessage) error {
+	var p statusParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if _, ok := statusConditions[strings.ToLower(p.Status)]; !ok {
+		return fmt.Errorf("unknown status %q", p.Status)
+	}
+
+	m.Effects = append(m.Effects, SecondaryEffect{Status: p.Status, Chance: p.Chance}.Apply)
+	return nil
+}
+
+type percentParams struct {
+	Percent int `json:"percent"`
+}
+
+// recoilEffect hurts the user by a percentage of the damage it dealt.
+func recoilEffect(m *Move, params json.RawMessage) error {
+	var p percentParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.Percent <= 0 {
+		return fmt.Errorf("percent must be positive")
+	}
+
+	m.Effects = append(m.Effects, func(user, _ *Pokemon, result DamageResult) {
+		if result.Damage > 0 {
+			user.TakeDamage(atLeastOne(result.Damage * p.Percent / 100))
+		}
+	})
+	return nil
+}
+
+// maxHPRecoilEffect hurts the user by a percentage of its own max HP, however much
+// damage it dealt, as Struggle does.
+func maxHPRecoilEffect(m *Move, params json.RawMessage) error {
+	var p percentParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.Percent <= 0 {
+		return fmt.Errorf("percent must be positive")
+	}
+
+	m.Effects = append(m.Effects, func(user, _ *Pokemon, _ DamageResult) {
+		user.TakeDamage(atLeastOne(user.Health.Max * p.Percent / 100))
+	})
+	return nil
+}
+
+// drainEffect heals the user by a percentage of the damage it dealt.
+func drainEffect(m *Move, params json.RawMessage) error {
+	var p percentParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.Percent <= 0 {
+		return fmt.Errorf("percent must be positive")
+	}
+
+	m.Effects = append(m.Effects, func(user, _ *Pokemon, result DamageResult) {
+		if result.Damage > 0 && !user.Health.IsFainted() {
+			user.Health.increase(atLeastOne(result.Damage * p.Percent / 100))
+		}
+	})
+	return nil
+}
+
+type multiHitParams struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+func multiHitEffect(m *Move, params json.RawMessage) error {
+	var p multiHitParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.Min < 1 || p.Max < p.Min {
+		return fmt.Errorf("invalid hits %d-%d", p.Min, p.Max)
+	}
+	m.MinHits, m.MaxHits = p.Min, p.Max
+	return nil
+}
+
+func atLeastOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// substituteEffect has the user put a quarter of its max HP into a substitute, as long
+// as it has more than that left and no substitute already.
+func substituteEffect(m *Move, _ json.RawMessage) error {
+	m.Effects = append(m.Effects, func(user, _ *Pokemon, _ DamageResult) {
+		cost := atLeastOne(user.Health.Max / 4)
+		if user.Health.Current <= cost || user.substitute() != nil {
+			return
+		}
+		user.TakeDamage(cost)
+		user.StatusManager.Inflict(user, &SubstituteStatus{HP: cost})
+	})
+	return nil
+}
@@ -0,0 +1,190 @@
+package pokemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/dghubble/trie"
+	"gopkg.in/yaml.v3"
+)
+
+type MoveDexRepository interface {
+	MoveRegistry
+	GetMoveByID(id int) *Move
+	SearchByNamePrefix(prefix string) []*Move
+	SearchByType(moveType Type) []*Move
+	SearchByCategory(category MoveCategory) []*Move
+	AddNewMove(newMove Move) error
+}
+
+// MoveDex holds move definitions, indexed the same way as the Pokedex.
+type MoveDex struct {
+	Version         string                   `json:"version,omitempty"`
+	Moves           []Move                   `json:"moves"`
+	indexByID       map[int]*Move            `json:"-"`
+	nameTrie        *trie.RuneTrie           `json:"-"`
+	typeHashMap     map[Type][]*Move         `json:"-"`
+	categoryHashMap map[MoveCategory][]*Move `json:"-"`
+}
+
+// NewMoveDex indexes moves, turning their EffectSpecs into Effects.
+func NewMoveDex(moves []Move) (*MoveDex, error) {
+	m := &MoveDex{Moves: moves}
+	if err := m.loadEffects(); err != nil {
+		return nil, err
+	}
+
+	m.buildIndices()
+	return m, nil
+}
+
+func LoadMoveDexFromJSON(handler FileIOHandler, filename string) (*MoveDex, error) {
+	bytes, err := handler.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMoveDex(bytes)
+}
+
+func LoadMoveDexFromYAML(handler FileIOHandler, filename string) (*MoveDex, error) {
+	bytes, err := handler.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMoveDexYAML(bytes)
+}
+
+// LoadMoveDexFromFS reads a MoveDex from any file system, as YAML for .yaml and .yml
+// files and as JSON otherwise.
+func LoadMoveDexFromFS(fsys fs.FS, name string) (*MoveDex, error) {
+	bytes, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch path.Ext(name) {
+	case ".yaml", ".yml":
+		return decodeMoveDexYAML(bytes)
+	}
+	return decodeMoveDex(bytes)
+}
+
+// decodeMoveDexYAML goes through JSON so that moves and their effect params decode
+// exactly the same way from either format.
+func decodeMoveDexYAML(bytes []byte) (*MoveDex, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(bytes, &doc); err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMoveDex(jsonBytes)
+}
+
+func decodeMoveDex(bytes []byte) (*MoveDex, error) {
+	var moveDex MoveDex
+	if err := json.Unmarshal(bytes, &moveDex); err != nil {
+		return nil, err
+	}
+
+	if err := moveDex.loadEffects(); err != nil {
+		return nil, err
+	}
+
+	moveDex.buildIndices()
+	return &moveDex, nil
+}
+
+func (m *MoveDex) SaveMoveDexToJSON(handler FileIOHandler, filename string) error {
+	// effects are saved through their EffectSpecs
+	return SaveTOJSON(handler, m, filename)
+}
+
+func (m *MoveDex) loadEffects() error {
+	for i := range m.Moves {
+		if err := m.Moves[i].LoadEffects(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MoveDex) buildIndices() {
+	m.indexByID = make(map[int]*Move)
+	m.nameTrie = trie.NewRuneTrie()
+	m.typeHashMap = make(map[Type][]*Move)
+	m.categoryHashMap = make(map[MoveCategory][]*Move)
+
+	for i := range m.Moves {
+		move := &m.Moves[i]
+		m.indexByID[move.ID] = move
+		m.nameTrie.Put(move.Name, move)
+		m.typeHashMap[move.Type] = append(m.typeHashMap[move.Type], move)
+		m.categoryHashMap[move.Category] = append(m.categoryHashMap[move.Category], move)
+	}
+}
+
+func (m *MoveDex) GetMoveByID(id int) *Move {
+	if move, exists := m.indexByID[id]; exists {
+		return move
+	}
+	return nil
+}
+
+// GetMoveByName returns a copy of the named move, which makes MoveDex a MoveRegistry.
+func (m *MoveDex) GetMoveByName(name string) (Move, bool) {
+	move, ok := m.nameTrie.Get(name).(*Move)
+	if !ok {
+		return Move{}, false
+	}
+	return *move, true
+}
+
+func (m *MoveDex) SearchByType(moveType Type) []*Move {
+	return m.typeHashMap[moveType]
+}
+
+func (m *MoveDex) SearchByCategory(category MoveCategory) []*Move {
+	return m.categoryHashMap[category]
+}
+
+func (m *MoveDex) SearchByNamePrefix(prefix string) []*Move {
+	var matchingMoves []*Move
+	err := m.nameTrie.Walk(func(word string, value interface{}) error {
+		if len(word) >= len(prefix) && word[:len(prefix)] == prefix {
+			move, ok := value.(*Move)
+			if ok {
+				matchingMoves = append(matchingMoves, move)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil
+	}
+
+	return matchingMoves
+}
+
+func (m *MoveDex) AddNewMove(newMove Move) error {
+	if _, exists := m.indexByID[newMove.ID]; exists {
+		return fmt.Errorf("move with ID: %d already exists", newMove.ID)
+	}
+	if _, exists := m.GetMoveByName(newMove.Name); exists {
+		return fmt.Errorf("move with name: %s already exists", newMove.Name)
+	}
+	if err := newMove.LoadEffects(); err != nil {
+		return err
+	}
+
+	m.Moves = append(m.Moves, newMove)
+
+	m.buildIndices()
+	return nil
+}
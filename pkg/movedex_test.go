@@ -0,0 +1,199 @@
+package pokemon
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func createTestMoveDex(t *testing.T) *MoveDex {
+	t.Helper()
+	moveDex, err := NewMoveDex([]Move{
+		{ID: 33, Name: "Tackle", Type: Normal, Category: Physical, Power: 40, PP: 35, Accuracy: 100},
+		{ID: 52, Name: "Ember", Type: Fire, Category: Special, Power: 40, PP: 25, Accuracy: 100},
+		{ID: 53, Name: "Flamethrower", Type: Fire, Category: Special, Power: 90, PP: 15, Accuracy: 100},
+		{ID: 45, Name: "Growl", Type: Normal, Category: Status, PP: 40, Accuracy: 100,
+			EffectSpecs: []EffectSpec{spec("stat_change", `{"stat":"Attack","stages":-1}`)}},
+	})
+	if err != nil {
+		t.Fatalf("NewMoveDex() error = %v", err)
+	}
+	return moveDex
+}
+
+func moveNames(moves []*Move) []string {
+	var names []string
+	for _, m := range moves {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+func TestMoveDexLookups(t *testing.T) {
+	moveDex := createTestMoveDex(t)
+
+	if move := moveDex.GetMoveByID(52); move == nil || move.Name != "Ember" {
+		t.Errorf("GetMoveByID(52) = %+v, want Ember", move)
+	}
+	if move := moveDex.GetMoveByID(1); move != nil {
+		t.Errorf("GetMoveByID(1) = %+v, want nil", move)
+	}
+
+	growl, ok := moveDex.GetMoveByName("Growl")
+	if !ok || len(growl.Effects) != 1 {
+		t.Errorf("GetMoveByName(Growl) = %+v, %v, want Growl with its effect loaded", growl, ok)
+	}
+	if _, ok := moveDex.GetMoveByName("Gro"); ok {
+		t.Errorf("GetMoveByName() should only match whole names")
+	}
+
+	if got := moveNames(moveDex.SearchByNamePrefix("F")); !reflect.DeepEqual(got, []string{"Flamethrower"}) {
+		t.Errorf("SearchByNamePrefix(F) = %v, want [Flamethrower]", got)
+	}
+	if got := moveNames(moveDex.SearchByType(Fire)); !reflect.DeepEqual(got, []string{"Ember", "Flamethrower"}) {
+		t.Errorf("SearchByType(Fire) = %v, want [Ember Flamethrower]", got)
+	}
+	if got := moveNames(moveDex.SearchByCategory(Status)); !reflect.DeepEqual(got, []string{"Growl"}) {
+		t.Errorf("SearchByCategory(Status) = %v, want [Growl]", got)
+	}
+	if got := moveDex.SearchByType(Dragon); len(got) != 0 {
+		t.Errorf("SearchByType(Dragon) = %v, want none", moveNames(got))
+	}
+}
+
+func TestAddNewMove(t *testing.T) {
+	moveDex := createTestMoveDex(t)
+
+	if err := moveDex.AddNewMove(Move{ID: 55, Name: "Water Gun", Type: Water, Category: Special, Power: 40}); err != nil {
+		t.Fatalf("AddNewMove() error = %v", err)
+	}
+	if move := moveDex.GetMoveByID(55); move == nil || len(moveDex.SearchByType(Water)) != 1 {
+		t.Errorf("Water Gun not indexed after AddNewMove()")
+	}
+	if got := len(moveDex.SearchByType(Fire)); got != 2 {
+		t.Errorf("SearchByType(Fire) returned %d moves after AddNewMove(), want 2", got)
+	}
+
+	tests := []struct {
+		name string
+		move Move
+	}{
+		{"duplicate ID", Move{ID: 33, Name: "Pound"}},
+		{"duplicate name", Move{ID: 1, Name: "Tackle"}},
+		{"bad effect", Move{ID: 2, Name: "Karate Chop", EffectSpecs: []EffectSpec{spec("teleport", ``)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := moveDex.AddNewMove(tt.move); err == nil {
+				t.Errorf("AddNewMove() expected an error")
+			}
+		})
+	}
+}
+
+func TestSaveAndLoadMoveDex(t *testing.T) {
+	original := createTestMoveDex(t)
+	handler := &MockFileIOHandler{FileData: make(map[string][]byte)}
+
+	if err := original.SaveMoveDexToJSON(handler, "moves.json"); err != nil {
+		t.Fatalf("Failed to save MoveDex to json: %v", err)
+	}
+	loaded, err := LoadMoveDexFromJSON(handler, "moves.json")
+	if err != nil {
+		t.Fatalf("Failed to load MoveDex from json: %v", err)
+	}
+
+	if len(loaded.Moves) != len(original.Moves) {
+		t.Fatalf("Loaded %d moves, want %d", len(loaded.Moves), len(original.Moves))
+	}
+	for i := range original.Moves {
+		want, got := original.Moves[i], loaded.Moves[i]
+		if got.Name != want.Name || got.Power != want.Power || len(got.Effects) != len(want.Effects) {
+			t.Errorf("Loaded move %+v, want %+v", got, want)
+		}
+	}
+}
+
+const testMoveDexYAML = `
+version: "2"
+moves:
+  - ID: 141
+    Name: Leech Life
+    Type: Bug
+    Category: Physical
+    Power: 80
+    PP: 10
+    Accuracy: 100
+    Effects:
+      - kind: drain
+        params: {percent: 50}
+  - ID: 31
+    Name: Fury Attack
+    Type: Normal
+    Category: Physical
+    Power: 15
+    PP: 20
+    Accuracy: 85
+    Effects:
+      - kind: multi_hit
+        params:
+          min: 2
+          max: 5
+`
+
+func TestLoadMoveDexFromYAML(t *testing.T) {
+	handler := &MockFileIOHandler{FileData: map[string][]byte{"moves.yaml": []byte(testMoveDexYAML)}}
+
+	moveDex, err := LoadMoveDexFromYAML(handler, "moves.yaml")
+	if err != nil {
+		t.Fatalf("LoadMoveDexFromYAML() error = %v", err)
+	}
+	if moveDex.Version != "2" || len(moveDex.Moves) != 2 {
+		t.Fatalf("Loaded version %q with %d moves, want 2 with 2", moveDex.Version, len(moveDex.Moves))
+	}
+
+	if leechLife := moveDex.GetMoveByID(141); leechLife == nil || leechLife.Type != Bug || len(leechLife.Effects) != 1 {
+		t.Errorf("Leech Life = %+v, want a Bug move with a drain effect", leechLife)
+	}
+	if furyAttack := moveDex.GetMoveByID(31); furyAttack == nil || furyAttack.MinHits != 2 || furyAttack.MaxHits != 5 {
+		t.Errorf("Fury Attack = %+v, want 2-5 hits", furyAttack)
+	}
+}
+
+func TestLoadMoveDexFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"moves.yml":  {Data: []byte(testMoveDexYAML)},
+		"moves.json": {Data: []byte(`{"moves": [{"ID": 33, "Name": "Tackle", "Type": "Normal", "Power": 40}]}`)},
+		"bad.json":   {Data: []byte(`{"moves": [{"Name": "Splash", "Effects": [{"kind": "nothing"}]}]}`)},
+	}
+
+	if moveDex, err := LoadMoveDexFromFS(fsys, "moves.yml"); err != nil || len(moveDex.Moves) != 2 {
+		t.Errorf("LoadMoveDexFromFS(moves.yml) = %v, %v, want 2 moves", moveDex, err)
+	}
+	if moveDex, err := LoadMoveDexFromFS(fsys, "moves.json"); err != nil || moveDex.GetMoveByID(33) == nil {
+		t.Errorf("LoadMoveDexFromFS(moves.json) = %v, %v, want Tackle", moveDex, err)
+	}
+	if _, err := LoadMoveDexFromFS(fsys, "bad.json"); err == nil {
+		t.Errorf("LoadMoveDexFromFS(bad.json) expected an error for the unknown effect")
+	}
+	if _, err := LoadMoveDexFromFS(fsys, "missing.json"); err == nil {
+		t.Errorf("LoadMoveDexFromFS(missing.json) expected an error")
+	}
+}
+
+func TestDefaultMoveDex(t *testing.T) {
+	moveDex, err := LoadDefaultMoveDex()
+	if err != nil {
+		t.Fatalf("LoadDefaultMoveDex() error = %v", err)
+	}
+
+	swordsDance := moveDex.GetMoveByID(14)
+	if swordsDance == nil || swordsDance.Name != "Swords Dance" || len(swordsDance.Effects) != 1 {
+		t.Fatalf("GetMoveByID(14) = %+v, want Swords Dance with an effect", swordsDance)
+	}
+	user := newDamageTestPokemon([]Type{Normal})
+	swordsDance.Execute(user, newDamageTestPokemon([]Type{Normal}))
+	if user.Modifiers.Attack != 2 {
+		t.Errorf("Swords Dance attack stage = %d, want +2", user.Modifiers.Attack)
+	}
+}
@@ -1,54 +1,180 @@
 package pokemon
 
-import "math/rand"
-
-type Effect func(*Pokemon, *Pokemon)
+// Effect runs after a move hits, result is the damage it did.
+type Effect func(user *Pokemon, target *Pokemon, result DamageResult)
 
 type MoveCategory string
 
 const (
 	Physical MoveCategory = "Physical"
 	Special  MoveCategory = "Special"
+	Status   MoveCategory = "Status"
 )
 
 type Move struct {
-	Name         string
-	Type         Type
-	Category     MoveCategory
-	Power        int
-	PP           int
-	Accuracy     int
-	Effects      []Effect
-	StatusEffect StatusEffect
-	Priority     int
-}
-
-func (m *Move) Execute(user *Pokemon, target *Pokemon) {
-	if rand.Intn(100) < m.Accuracy {
-		for _, effect := range m.Effects {
-			effect(user, target)
-		}
+	ID       int
+	Name     string
+	Type     Type
+	Category MoveCategory
+	Power    int
+	PP       int
+	Accuracy int // 0 for moves that never miss
+	Priority int
+	// Typeless moves, like Struggle, ignore the type chart and get no STAB.
+	Typeless bool `json:",omitempty"`
+	// TargetsUser is set on moves that only affect the user or its side, like Swords
+	// Dance or Reflect, which can't miss.
+	TargetsUser bool `json:",omitempty"`
+	// MinHits and MaxHits make the move hit several times, 0 means once.
+	MinHits int `json:",omitempty"`
+	MaxHits int `json:",omitempty"`
+	// EffectSpecs declare the move's effects, LoadEffects turns them into Effects.
+	EffectSpecs []EffectSpec `json:"Effects,omitempty"`
+	Effects     []Effect     `json:"-"`
+}
+
+// MoveRegistry looks up complete move definitions, used to resolve moves that are
+// stored by name such as those in a Learnset.
+type MoveRegistry interface {
+	GetMoveByName(name string) (Move, bool)
+}
 
-		if m.StatusEffect != nil {
-			m.StatusEffect.Apply(target)
+// Execute rolls for accuracy, deals damage once per hit and applies the move's effects.
+func (m *Move) Execute(user *Pokemon, target *Pokemon) DamageResult {
+	if !m.alwaysHits() && rng.Intn(100) >= m.hitChance(user, target) {
+		return DamageResult{Missed: true}
+	}
+
+	var result DamageResult
+	sub := target.substitute()
+	if target == user {
+		sub = nil
+	}
+	hits := m.hits()
+	for i := 0; i < hits && (i == 0 || !target.Health.IsFainted()); i++ {
+		hit := CalculateDamage(user, target, m)
+		if sub != nil {
+			sub.absorb(target, hit.Damage)
+			result.Substitute = true
+		} else if hit.Damage > 0 {
+			target.TakeDamage(hit.Damage)
 		}
+		result.Damage += hit.Damage
+		result.Critical = result.Critical || hit.Critical
+		result.STAB, result.Effectiveness = hit.STAB, hit.Effectiveness
+		result.Hits++
+		if hit.Effectiveness == 0 || (sub != nil && sub.HP <= 0) {
+			break
+		}
+	}
+	if result.Effectiveness == 0 && m.Power > 0 {
+		return result
+	}
+	thawIfFire(target, m, result)
+
+	for _, effect := range m.Effects {
+		effect(user, target, result)
+	}
+	return result
+}
+
+// alwaysHits reports whether the move skips the accuracy roll, so evasion can't make
+// it miss either.
+func (m *Move) alwaysHits() bool {
+	return m.Accuracy == 0 || m.TargetsUser
+}
+
+// hitChance is the move's accuracy in percent, scaled by the user's accuracy stage
+// against the target's evasion stage.
+func (m *Move) hitChance(user, target *Pokemon) int {
+	stage := user.Modifiers.Accuracy - target.Modifiers.Evasion
+	return int(float64(m.Accuracy) * accuracyStageMultiplier(stage))
+}
+
+// hits rolls how many times a multi-hit move hits.
+func (m *Move) hits() int {
+	if m.MaxHits <= 1 {
+		return 1
+	}
+	if m.MinHits < 1 || m.MinHits >= m.MaxHits {
+		return m.MaxHits
 	}
+	return m.MinHits + rng.Intn(m.MaxHits-m.MinHits+1)
 }
 
-// Example of defining a stat-boosting effect
-var attackBoost = func(user *Pokemon, _ *Pokemon) {
-	user.Modifiers.AttackMultiplier *= 2.0
-	if user.Modifiers.AttackMultiplier > 4.0 {
-		user.Modifiers.AttackMultiplier = 4.0
+const maxPPUps = 3
+
+// MoveSlot is one of a Pokemon's moves with the PP it has left, which belongs to the
+// Pokemon rather than the shared Move.
+type MoveSlot struct {
+	Move  Move
+	PP    int
+	PPUps int // each PP Up raises the maximum PP by a fifth of the move's base PP
+}
+
+// NewMoveSlot holds move with full PP.
+func NewMoveSlot(move Move) MoveSlot {
+	return MoveSlot{Move: move, PP: move.PP}
+}
+
+func (s *MoveSlot) Empty() bool {
+	return s.Move.Name == ""
+}
+
+func (s *MoveSlot) MaxPP() int {
+	return s.Move.PP + s.Move.PP*s.PPUps/5
+}
+
+// CanUse reports whether the slot holds a move with PP left.
+func (s *MoveSlot) CanUse() bool {
+	return !s.Empty() && s.PP > 0
+}
+
+// UsePP spends one PP, returning false if there was none left.
+func (s *MoveSlot) UsePP() bool {
+	if !s.CanUse() {
+		return false
+	}
+	s.PP--
+	return true
+}
+
+// RestorePP gives back up to amount PP, returning how much was restored.
+func (s *MoveSlot) RestorePP(amount int) int {
+	if s.Empty() {
+		return 0
+	}
+	restored := min(amount, s.MaxPP()-s.PP)
+	s.PP += restored
+	return restored
+}
+
+// AddPPUps raises the maximum PP by up to ups PP Ups, the extra PP is usable straight away.
+func (s *MoveSlot) AddPPUps(ups int) bool {
+	if s.Empty() || s.Move.PP < 5 || s.PPUps >= maxPPUps {
+		return false
 	}
+	maxPP := s.MaxPP()
+	s.PPUps = min(s.PPUps+ups, maxPPUps)
+	s.PP += s.MaxPP() - maxPP
+	return true
 }
 
-// Example move with multiple effects
-var buffBuff = Move{
-	Name:     "Buff buff",
-	Type:     Normal,
-	Power:    0,
-	Accuracy: 100,
-	PP:       8,
-	Effects:  []Effect{attackBoost, attackBoost}, // should apply both example effects, will probably move to test code or something
+// Struggle is used when a Pokemon has no PP left in any of its moves.
+var Struggle = newStruggle()
+
+func newStruggle() Move {
+	struggle := Move{
+		ID:          165,
+		Name:        "Struggle",
+		Typeless:    true,
+		Category:    Physical,
+		Power:       50,
+		Accuracy:    100,
+		EffectSpecs: []EffectSpec{{Kind: "recoil_max_hp", Params: []byte(`{"percent":25}`)}},
+	}
+	if err := struggle.LoadEffects(); err != nil {
+		panic("pokemon: Struggle: " + err.Error())
+	}
+	return struggle
 }
@@ -0,0 +1,226 @@
+package pokemon
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func newEffectMove(t *testing.T, power int, specs ...EffectSpec) Move {
+	t.Helper()
+	category := Physical
+	if power == 0 {
+		category = Status
+	}
+	move := Move{Name: "Test", Type: Normal, Category: category, Power: power, Accuracy: 100, EffectSpecs: specs}
+	if err := move.LoadEffects(); err != nil {
+		t.Fatalf("LoadEffects() error = %v", err)
+	}
+	return move
+}
+
+func spec(kind, params string) EffectSpec {
+	return EffectSpec{Kind: kind, Params: json.RawMessage(params)}
+}
+
+func TestMoveEffects(t *testing.T) {
+	tests := []struct {
+		name  string
+		power int
+		spec  EffectSpec
+		check func(t *testing.T, user, target *Pokemon, result DamageResult)
+	}{
+		{"stat change on target", 0, spec("stat_change", `{"stat": "Attack", "stages": -1}`),
+			func(t *testing.T, user, target *Pokemon, _ DamageResult) {
+				if target.Modifiers.Attack != -1 || user.Modifiers.Attack != 0 {
+					t.Errorf("attack stages user %d, target %d, want 0 and -1", user.Modifiers.Attack, target.Modifiers.Attack)
+				}
+			}},
+		{"stat change on user", 0, spec("stat_change", `{"stat": "speed", "stages": 2, "target": "user"}`),
+			func(t *testing.T, user, _ *Pokemon, _ DamageResult) {
+				if user.Modifiers.Speed != 2 {
+					t.Errorf("user speed stage = %d, want +2", user.Modifiers.Speed)
+				}
+			}},
+		{"stat change chance missed", 80, spec("stat_change", `{"stat": "Defense", "stages": -1, "chance": 10}`),
+			func(t *testing.T, _, target *Pokemon, _ DamageResult) {
+				if target.Modifiers.Defense != 0 {
+					t.Errorf("target defense stage = %d, want unchanged", target.Modifiers.Defense)
+				}
+			}},
+		{"status", 0, spec("status", `{"status": "poison"}`),
+			func(t *testing.T, _, target *Pokemon, _ DamageResult) {
+				if _, ok := target.StatusManager.Primary.(*PoisonStatus); !ok {
+					t.Errorf("target status = %v, want poison", target.StatusManager.Primary)
+				}
+			}},
+		{"recoil", 80, spec("recoil", `{"percent": 25}`),
+			func(t *testing.T, user, _ *Pokemon, result DamageResult) {
+				if want := 200 - result.Damage/4; user.Health.Current != want {
+					t.Errorf("user health = %d, want %d", user.Health.Current, want)
+				}
+			}},
+		{"max HP recoil", 80, spec("recoil_max_hp", `{"percent": 25}`),
+			func(t *testing.T, user, _ *Pokemon, _ DamageResult) {
+				if user.Health.Current != 150 {
+					t.Errorf("user health = %d, want 150", user.Health.Current)
+				}
+			}},
+		{"multi hit", 80, spec("multi_hit", `{"min": 2, "max": 5}`),
+			func(t *testing.T, _, target *Pokemon, result DamageResult) {
+				if result.Hits != 5 || result.Damage != 5*37 || target.Health.Current != 200-5*37 {
+					t.Errorf("result %+v, target health %d, want 5 hits of 37", result, target.Health.Current)
+				}
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRandomizer(t, highRolls)
+			user := newDamageTestPokemon([]Type{Fire})
+			target := newDamageTestPokemon([]Type{Normal})
+			move := newEffectMove(t, tt.power, tt.spec)
+
+			result := move.Execute(user, target)
+			tt.check(t, user, target, result)
+		})
+	}
+}
+
+func TestDrainEffect(t *testing.T) {
+	setRandomizer(t, highRolls)
+	user := newDamageTestPokemon([]Type{Fire})
+	user.Health.Current = 100
+	target := newDamageTestPokemon([]Type{Normal})
+	move := newEffectMove(t, 80, spec("drain", `{"percent": 50}`))
+
+	result := move.Execute(user, target)
+
+	if want := 100 + result.Damage/2; user.Health.Current != want {
+		t.Errorf("user health = %d, want %d", user.Health.Current, want)
+	}
+}
+
+func TestMultiHitStopsWhenTargetFaints(t *testing.T) {
+	setRandomizer(t, highRolls)
+	user := newDamageTestPokemon([]Type{Fire})
+	target := newDamageTestPokemon([]Type{Normal})
+	target.Health.Current = 50
+	move := newEffectMove(t, 80, spec("multi_hit", `{"min": 2, "max": 5}`))
+
+	if result := move.Execute(user, target); result.Hits != 2 {
+		t.Errorf("Execute() hit %d times, want 2 before the target fainted", result.Hits)
+	}
+}
+
+func TestEffectsSkippedOnImmuneTarget(t *testing.T) {
+	setRandomizer(t, highRolls)
+	user := newDamageTestPokemon([]Type{Fire})
+	target := newDamageTestPokemon([]Type{Ghost})
+	move := newEffectMove(t, 80, spec("recoil", `{"percent": 25}`))
+
+	result := move.Execute(user, target)
+
+	if result.Damage != 0 || user.Health.Current != 200 {
+		t.Errorf("Execute() on an immune target = %+v with user health %d, want no damage or recoil", result, user.Health.Current)
+	}
+}
+
+func TestStatusMovesRespectImmunities(t *testing.T) {
+	setRandomizer(t, highRolls)
+	user := newDamageTestPokemon([]Type{Electric})
+	target := newDamageTestPokemon([]Type{Ground})
+	thunderWave := newEffectMove(t, 0, spec("status", `{"status": "paralysis"}`))
+	thunderWave.Type = Electric
+
+	if result := thunderWave.Execute(user, target); result.Effectiveness != 0 || target.StatusManager.Primary != nil {
+		t.Errorf("Thunder Wave on a Ground type = %+v with status %v, want no effect", result, target.StatusManager.Primary)
+	}
+
+	// immunities only stop statuses, Growl still lowers a Ghost's attack
+	ghost := newDamageTestPokemon([]Type{Ghost})
+	growl := newEffectMove(t, 0, spec("stat_change", `{"stat": "Attack", "stages": -1}`))
+	growl.Execute(user, ghost)
+	if ghost.Modifiers.Attack != -1 {
+		t.Errorf("Growl on a Ghost type left attack at stage %d, want -1", ghost.Modifiers.Attack)
+	}
+}
+
+func TestStruggleHitsGhosts(t *testing.T) {
+	setRandomizer(t, highRolls)
+	user := newDamageTestPokemon([]Type{Normal})
+	target := newDamageTestPokemon([]Type{Ghost})
+
+	result := Struggle.Execute(user, target)
+
+	if result.Damage == 0 || result.Effectiveness != 1 || result.STAB {
+		t.Errorf("Struggle on a Ghost type = %+v, want neutral damage without STAB", result)
+	}
+	if user.Health.Current != 150 {
+		t.Errorf("user health = %d, want 150 after losing a quarter of its max HP", user.Health.Current)
+	}
+}
+
+func TestLoadEffectsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec EffectSpec
+	}{
+		{"unknown kind", spec("teleport", ``)},
+		{"unknown stat", spec("stat_change", `{"stat": "Luck", "stages": 1}`)},
+		{"HP stage", spec("stat_change", `{"stat": "HP", "stages": 1}`)},
+		{"unknown target", spec("stat_change", `{"stat": "Attack", "stages": 1, "target": "ally"}`)},
+		{"unknown status", spec("status", `{"status": "nightmare"}`)},
+		{"no recoil", spec("recoil", `{}`)},
+		{"invalid hits", spec("multi_hit", `{"min": 3, "max": 2}`)},
+		{"bad params", spec("drain", `{"percent": "half"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			move := Move{Name: "Test", EffectSpecs: []EffectSpec{tt.spec}}
+			if err := move.LoadEffects(); err == nil {
+				t.Errorf("LoadEffects() expected an error for %s %s", tt.spec.Kind, tt.spec.Params)
+			}
+		})
+	}
+}
+
+func TestMoveSlotPP(t *testing.T) {
+	slot := NewMoveSlot(Move{Name: "Tackle", PP: 35})
+
+	if !slot.UsePP() || slot.PP != 34 {
+		t.Fatalf("UsePP() left %d PP, want 34", slot.PP)
+	}
+	if restored := slot.RestorePP(10); restored != 1 || slot.PP != 35 {
+		t.Errorf("RestorePP(10) restored %d to %d PP, want 1 to 35", restored, slot.PP)
+	}
+
+	slot.PP = 0
+	if slot.UsePP() || slot.CanUse() {
+		t.Errorf("UsePP() should fail without PP")
+	}
+
+	var empty MoveSlot
+	if empty.CanUse() || empty.RestorePP(10) != 0 || empty.AddPPUps(1) {
+		t.Errorf("An empty slot should have nothing to use or restore")
+	}
+}
+
+func TestMoveSlotPPUps(t *testing.T) {
+	slot := NewMoveSlot(Move{Name: "Tackle", PP: 35})
+
+	if !slot.AddPPUps(1) || slot.MaxPP() != 42 || slot.PP != 42 {
+		t.Errorf("After a PP Up max PP = %d with %d PP, want 42 and 42", slot.MaxPP(), slot.PP)
+	}
+	if !slot.AddPPUps(maxPPUps) || slot.MaxPP() != 56 || slot.PPUps != maxPPUps {
+		t.Errorf("After PP Max max PP = %d with %d ups, want 56 and %d", slot.MaxPP(), slot.PPUps, maxPPUps)
+	}
+	if slot.AddPPUps(1) {
+		t.Errorf("AddPPUps() should fail past %d PP Ups", maxPPUps)
+	}
+
+	sketch := NewMoveSlot(Move{Name: "Sketch", PP: 1})
+	if sketch.AddPPUps(1) {
+		t.Errorf("Moves with less than 5 PP can't take PP Ups")
+	}
+}
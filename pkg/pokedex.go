@@ -3,7 +3,8 @@ package pokemon
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
+	"io/fs"
 
 	"github.com/dghubble/trie"
 )
@@ -17,6 +18,7 @@ type PokedexRepository interface {
 }
 
 type Pokedex struct {
+	Version     string              `json:"version,omitempty"`
 	Species     []Species           `json:"species"`
 	indexByID   map[int]*Species    `json:"-"`
 	nameTrie    *trie.RuneTrie      `json:"-"`
@@ -35,30 +37,64 @@ func NewPokedex(species []Species) *Pokedex {
 	return p
 }
 
-func LoadPokedexFromJSON(filename string) (*Pokedex, error) {
-	bytes, err := os.ReadFile(filename)
+// LoadPokedexFromJSON reads a Pokedex saved with SavePokedexToJSON. The file is left untouched.
+func LoadPokedexFromJSON(handler FileIOHandler, filename string) (*Pokedex, error) {
+	bytes, err := handler.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
+	return decodePokedex(bytes)
+}
+
+func LoadPokedexFromReader(r io.Reader) (*Pokedex, error) {
+	bytes, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return decodePokedex(bytes)
+}
+
+// LoadPokedexFromFS reads a Pokedex from any file system, such as an embed.FS.
+func LoadPokedexFromFS(fsys fs.FS, name string) (*Pokedex, error) {
+	bytes, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return decodePokedex(bytes)
+}
 
+func decodePokedex(bytes []byte) (*Pokedex, error) {
 	var pokedex Pokedex
 	if err := json.Unmarshal(bytes, &pokedex); err != nil {
 		return nil, err
 	}
 
+	if err := pokedex.ResolveLearnsets(DefaultMoveRegistry); err != nil {
+		return nil, err
+	}
+
 	pokedex.buildIndices()
-	os.Remove(filename)
 	return &pokedex, nil
 }
 
-func (p *Pokedex) SavePokedexToJSON(filename string) error {
+func (p *Pokedex) SavePokedexToJSON(handler FileIOHandler, filename string) error {
 	//only Species will get serialized to json
-	bytes, err := json.Marshal(p)
-	if err != nil {
-		return err
-	}
+	return SaveTOJSON(handler, p, filename)
+}
 
-	return os.WriteFile(filename, bytes, 0644)
+// ResolveLearnsets replaces the moves in every learnset, which only carry a name after
+// loading from JSON, with the full moves from the registry.
+func (p *Pokedex) ResolveLearnsets(moves MoveRegistry) error {
+	for i := range p.Species {
+		for j, entry := range p.Species[i].Learnset {
+			move, exists := moves.GetMoveByName(entry.Move.Name)
+			if !exists {
+				return fmt.Errorf("%s learns unknown move %q", p.Species[i].Name, entry.Move.Name)
+			}
+			p.Species[i].Learnset[j].Move = move
+		}
+	}
+	return nil
 }
 
 func (p *Pokedex) buildIndices() {
@@ -3,8 +3,11 @@ package pokemon
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func createTestPokedex() *Pokedex {
@@ -21,12 +24,13 @@ func TestSaveAndLoadPokedex(t *testing.T) {
 	originalPokdex := createTestPokedex()
 
 	filename := "test_pokedex.json"
+	handler := &MockFileIOHandler{FileData: make(map[string][]byte)}
 
-	if err := originalPokdex.SavePokedexToJSON(filename); err != nil {
+	if err := originalPokdex.SavePokedexToJSON(handler, filename); err != nil {
 		t.Fatalf("Failed to save Pokedex to json: %v", err)
 	}
 
-	loadedPokedex, err := LoadPokedexFromJSON(filename)
+	loadedPokedex, err := LoadPokedexFromJSON(handler, filename)
 
 	if err != nil {
 		t.Fatalf("Failed to load Pokedex from json: %v", err)
@@ -38,6 +42,101 @@ func TestSaveAndLoadPokedex(t *testing.T) {
 	}
 }
 
+func TestSaveAndLoadPokedexWithEvolutionsAndLearnsets(t *testing.T) {
+	tackle := Move{Name: "Tackle", Type: Normal, Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	ember := Move{Name: "Ember", Type: Fire, Category: Special, Power: 40, PP: 25, Accuracy: 100}
+	registry := DefaultMoveRegistry
+	moveDex, err := NewMoveDex([]Move{tackle, ember})
+	if err != nil {
+		t.Fatalf("NewMoveDex() error = %v", err)
+	}
+	DefaultMoveRegistry = moveDex
+	t.Cleanup(func() { DefaultMoveRegistry = registry })
+
+	originalPokedex := NewPokedex([]Species{
+		{ID: 4, Name: "Charmander", Types: []Type{Fire}, GrowthRate: MediumSlow,
+			EvolutionStages: []EvolutionStage{NewEvolutionStage(5, LevelEvolution{RequiredLevel: 16})},
+			Learnset:        Learnset{{Level: 1, Move: tackle}, {Level: 7, Move: ember}}},
+		{ID: 61, Name: "Poliwhirl", Types: []Type{Water},
+			EvolutionStages: []EvolutionStage{
+				NewEvolutionStage(62, ItemEvolution{RequiredItem: WaterStone}),
+				NewEvolutionStage(186, TradeEvolution{HeldItem: KingsRock}),
+			}},
+		{ID: 133, Name: "Eevee", Types: []Type{Normal},
+			EvolutionStages: []EvolutionStage{
+				NewEvolutionStage(196, FriendshipEvolution{RequiredFriendship: 220, RequiredTime: "Day"}),
+				NewEvolutionStage(470, LocationEvolution{Location: "Moss Rock"}),
+				NewEvolutionStage(700, AndEvolution{Methods: []EvolutionMethod{
+					FriendshipEvolution{RequiredFriendship: 160},
+					KnowsMoveEvolution{MoveName: "Charm"},
+				}}),
+			}},
+		{ID: 281, Name: "Kirlia", Types: []Type{Psychic},
+			EvolutionStages: []EvolutionStage{
+				NewEvolutionStage(282, LevelEvolution{RequiredLevel: 30}),
+				NewEvolutionStage(475, GenderEvolution{Gender: Male, RequiredItem: DawnStone}),
+			}},
+		{ID: 236, Name: "Tyrogue", Types: []Type{Fighting},
+			EvolutionStages: []EvolutionStage{
+				NewEvolutionStage(106, StatComparisonEvolution{RequiredLevel: 20, Comparison: AttackHigher}),
+				NewEvolutionStage(237, OrEvolution{Methods: []EvolutionMethod{
+					StatComparisonEvolution{RequiredLevel: 20, Comparison: AttackDefenseEqual},
+					HeldItemEvolution{RequiredItem: RazorClaw, RequiredTime: "Night"},
+				}}),
+			}},
+	})
+
+	handler := &MockFileIOHandler{FileData: make(map[string][]byte)}
+	if err := originalPokedex.SavePokedexToJSON(handler, "pokedex.json"); err != nil {
+		t.Fatalf("Failed to save Pokedex to json: %v", err)
+	}
+
+	loadedPokedex, err := LoadPokedexFromJSON(handler, "pokedex.json")
+	if err != nil {
+		t.Fatalf("Failed to load Pokedex from json: %v", err)
+	}
+
+	if !reflect.DeepEqual(originalPokedex.Species, loadedPokedex.Species) {
+		t.Errorf("Loaded species = %+v, want %+v", loadedPokedex.Species, originalPokedex.Species)
+	}
+
+	charmander := NewPokemon(loadedPokedex.GetSpeciesByID(4), 16, nil, nil, [4]Move{})
+	if into, err := charmander.FindEvolution(loadedPokedex, EvolutionContext{}); err == nil || into != nil {
+		t.Errorf("FindEvolution() = %v, %v, want an error for the missing species", into, err)
+	}
+}
+
+func TestLoadPokedexFromJSON_UnknownReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"unknown move", `{"species":[{"ID":1,"Name":"Bulbasaur","Learnset":[{"Level":1,"Move":"Not A Move"}]}]}`},
+		{"unknown item", `{"species":[{"ID":1,"Name":"Bulbasaur","EvolutionStages":[{"EvolvesInto":2,"Method":{"kind":"item","data":{"RequiredItem":"not-an-item"}}}]}]}`},
+		{"unknown kind", `{"species":[{"ID":1,"Name":"Bulbasaur","EvolutionStages":[{"EvolvesInto":2,"Method":{"kind":"spin"}}]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &MockFileIOHandler{FileData: map[string][]byte{"pokedex.json": []byte(tt.json)}}
+			if _, err := LoadPokedexFromJSON(handler, "pokedex.json"); err == nil {
+				t.Errorf("Expected error loading %s, got nil", tt.name)
+			}
+		})
+	}
+}
+
+func TestSavePokedexUnregisteredEvolutionMethod(t *testing.T) {
+	pokedex := NewPokedex([]Species{
+		{ID: 1, Name: "Bulbasaur", EvolutionStages: []EvolutionStage{NewEvolutionStage(2, tradeOnly{})}},
+	})
+
+	handler := &MockFileIOHandler{FileData: make(map[string][]byte)}
+	if err := pokedex.SavePokedexToJSON(handler, "pokedex.json"); err == nil {
+		t.Errorf("Expected error saving an unregistered evolution method, got nil")
+	}
+}
+
 func TestAddNewSpecies(t *testing.T) {
 	pokedex := createTestPokedex()
 
@@ -121,21 +220,61 @@ func TestLargePokedex(t *testing.T) {
 }
 
 func TestLoadPokedexFromJSON_NonExistentFile(t *testing.T) {
-	_, err := LoadPokedexFromJSON("non_existent_file.json")
+	_, err := LoadPokedexFromJSON(&OSFileIOHandler{}, "non_existent_file.json")
 	if err == nil {
 		t.Errorf("Expected error when loading from non-existent file, got nil")
 	}
 }
 
 func TestLoadPokedexFromJSON_InvalidJSON(t *testing.T) {
-	filename := "invalid.json"
-	err := os.WriteFile(filename, []byte("{invalid json}"), 0644)
-	if err != nil {
-		t.Fatalf("Failed to create invalid json file: %v", err)
-	}
-	_, err = LoadPokedexFromJSON(filename)
+	handler := &MockFileIOHandler{FileData: map[string][]byte{"invalid.json": []byte("{invalid json}")}}
+	_, err := LoadPokedexFromJSON(handler, "invalid.json")
 	if err == nil {
 		t.Errorf("Expected error when loading from invalid json file, got nil")
 	}
-	os.Remove(filename)
+}
+
+func TestLoadPokedexFromJSON_KeepsFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "pokedex.json")
+	handler := &OSFileIOHandler{}
+	if err := createTestPokedex().SavePokedexToJSON(handler, filename); err != nil {
+		t.Fatalf("Failed to save Pokedex to json: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := LoadPokedexFromJSON(handler, filename); err != nil {
+			t.Fatalf("Load %d failed: %v", i+1, err)
+		}
+	}
+	if _, err := os.Stat(filename); err != nil {
+		t.Errorf("Expected the file to still exist after loading, got %v", err)
+	}
+}
+
+func TestLoadPokedexFromReader(t *testing.T) {
+	pokedex, err := LoadPokedexFromReader(strings.NewReader(`{"species":[{"ID":4,"Name":"Charmander","Types":["Fire"]}]}`))
+	if err != nil {
+		t.Fatalf("LoadPokedexFromReader() error = %v", err)
+	}
+	if species := pokedex.GetSpeciesByID(4); species == nil || species.Name != "Charmander" {
+		t.Errorf("GetSpeciesByID(4) = %v, want Charmander", species)
+	}
+}
+
+func TestLoadPokedexFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"data/pokedex.json": {Data: []byte(`{"species":[{"ID":26,"Name":"Raichu","Types":["Electric"]}]}`)},
+	}
+
+	pokedex, err := LoadPokedexFromFS(fsys, "data/pokedex.json")
+	if err != nil {
+		t.Fatalf("LoadPokedexFromFS() error = %v", err)
+	}
+	if results := pokedex.SearchByType(Electric); len(results) != 1 || results[0].Name != "Raichu" {
+		t.Errorf("SearchByType(Electric) = %v, want Raichu", results)
+	}
+
+	if _, err := LoadPokedexFromFS(fsys, "missing.json"); err == nil {
+		t.Errorf("Expected error when loading a missing file, got nil")
+	}
 }
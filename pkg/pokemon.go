@@ -2,16 +2,19 @@ package pokemon
 
 import (
 	"fmt"
-	"math/rand"
+	"strings"
 )
 
-// These should reset at end of battle or if pokemon is switched out
+// StatModifiers are a Pokemon's stat stages in battle, from -6 to +6. They reset at
+// the end of battle or when the Pokemon is switched out.
 type StatModifiers struct {
-	AttackMultiplier         float32
-	DefenseMultplier         float32
-	SpecialAttackMultiplier  float32
-	SpecialDefenseMultiplier float32
-	SpeedMultiplier          float32
+	Attack         int
+	Defense        int
+	SpecialAttack  int
+	SpecialDefense int
+	Speed          int
+	Accuracy       int
+	Evasion        int
 }
 
 type Stats struct {
@@ -23,6 +26,69 @@ type Stats struct {
 	Speed          int
 }
 
+// Stat identifies one of the fields in Stats.
+type Stat int
+
+const (
+	StatHP Stat = iota
+	StatAttack
+	StatDefense
+	StatSpecialAttack
+	StatSpecialDefense
+	StatSpeed
+	// StatAccuracy and StatEvasion only exist as stat stages.
+	StatAccuracy
+	StatEvasion
+)
+
+func (s Stat) String() string {
+	return [...]string{"HP", "Attack", "Defense", "SpecialAttack", "SpecialDefense", "Speed", "Accuracy", "Evasion"}[s]
+}
+
+func StringToStat(s string) (Stat, error) {
+	for stat := StatHP; stat <= StatEvasion; stat++ {
+		if strings.EqualFold(stat.String(), s) {
+			return stat, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown stat %q", s)
+}
+
+func (s Stats) Get(stat Stat) int {
+	switch stat {
+	case StatHP:
+		return s.HP
+	case StatAttack:
+		return s.Attack
+	case StatDefense:
+		return s.Defense
+	case StatSpecialAttack:
+		return s.SpecialAttack
+	case StatSpecialDefense:
+		return s.SpecialDefense
+	case StatSpeed:
+		return s.Speed
+	}
+	return 0
+}
+
+func (s *Stats) Set(stat Stat, value int) {
+	switch stat {
+	case StatHP:
+		s.HP = value
+	case StatAttack:
+		s.Attack = value
+	case StatDefense:
+		s.Defense = value
+	case StatSpecialAttack:
+		s.SpecialAttack = value
+	case StatSpecialDefense:
+		s.SpecialDefense = value
+	case StatSpeed:
+		s.Speed = value
+	}
+}
+
 type Health struct {
 	Current int
 	Max     int
@@ -54,6 +120,14 @@ func (h *Health) IsFainted() bool {
 	return h.Current <= 0
 }
 
+type Gender int
+
+const (
+	Genderless Gender = iota
+	Male
+	Female
+)
+
 type Pokemon struct {
 	Species       *Species
 	Health        Health
@@ -63,175 +137,311 @@ type Pokemon struct {
 	StatusManager StatusEffectManager
 	Stats         Stats
 	ivs           Stats
+	evs           Stats
 	Friendship    int
+	Gender        Gender
 	Nature        *Nature
-	Moves         [4]Move
+	Moves         [4]MoveSlot
 	Modifiers     StatModifiers
+	lastMove      string // the move it used last in battle, for Encore
 }
 
-func (p *Pokemon) LevelUp() {
-	oldMaxHealth := p.Health.Max
-	p.Level++
-	fmt.Println(p.Species.Name, "leveled up to ", p.Level)
-	p.Stats = CalculateStats(p.Stats, p.Level, p.ivs)
-
-	healthIncrease := p.Stats.HP - oldMaxHealth
+// LevelUpResult describes what happened on a single level up.
+type LevelUpResult struct {
+	Level        int
+	LearnedMoves []Move
+	// PendingMoves couldn't be learned because every move slot is taken, the caller decides what to forget.
+	PendingMoves []MoveLearnRequest
+}
 
-	p.Health.increase(healthIncrease)
+func (p *Pokemon) LevelUp() LevelUpResult {
+	p.Level++
+	p.RecalculateStats()
 
-	if p.Health.Current > p.Health.Max {
-		p.Health.Current = p.Health.Max
+	result := LevelUpResult{Level: p.Level}
+	for _, move := range p.Species.Learnset.MovesAt(p.Level) {
+		if p.KnowsMove(move.Name) {
+			continue
+		}
+		if p.LearnMove(move) {
+			result.LearnedMoves = append(result.LearnedMoves, move)
+		} else {
+			result.PendingMoves = append(result.PendingMoves, MoveLearnRequest{Pokemon: p, Move: move})
+		}
 	}
 
-	// Should probably check for new moves to learn or if evolution happens.
+	// Evolution is left to an EvolutionEngine, LevelUp happens mid-battle too.
+	return result
 }
 
-func (p *Pokemon) Heal(item Item) error {
-	// check if pokemon is fainted, return early with error if so, dont consume item then.
-	if p.Health.IsFainted() {
-		if reviveItem, ok := item.(ReviveItem); ok {
-			reviveItem.Revive(p)
-			return nil
-		} else {
-			return fmt.Errorf("Cannot heal a fainted pokemon with %s", item.Name())
-
+func (p *Pokemon) KnowsMove(name string) bool {
+	for i := range p.Moves {
+		if !p.Moves[i].Empty() && p.Moves[i].Move.Name == name {
+			return true
 		}
 	}
-	if p.Health.Current >= p.Health.Max {
-		return fmt.Errorf("Cannot heal a pokemon with full health")
-	}
-	item.Use(p)
+	return false
+}
 
-	return nil
+// LearnMove puts move in the first empty slot with full PP, returning false if there is none.
+func (p *Pokemon) LearnMove(move Move) bool {
+	for i := range p.Moves {
+		if p.Moves[i].Empty() {
+			p.Moves[i] = NewMoveSlot(move)
+			return true
+		}
+	}
+	return false
 }
 
-func (p *Pokemon) TakeDamage(amount int) {
-	p.Health.decrease(amount)
+// HasPP reports whether any of the Pokemon's moves can still be used, otherwise it has to Struggle.
+func (p *Pokemon) HasPP() bool {
+	for i := range p.Moves {
+		if p.Moves[i].CanUse() {
+			return true
+		}
+	}
+	return false
+}
 
-	if p.Health.IsFainted() {
-		p.StatusManager.Primary = &FaintedStatus{}
+// FirstUsableMove returns the first move slot with PP left.
+func (p *Pokemon) FirstUsableMove() (int, bool) {
+	for i := range p.Moves {
+		if p.Moves[i].CanUse() {
+			return i, true
+		}
 	}
+	return 0, false
 }
 
-func (p *Pokemon) HasItem(item Item) bool {
-	return true
+// RestoreAllPP refills every move, as a Pokemon Center does.
+func (p *Pokemon) RestoreAllPP() {
+	for i := range p.Moves {
+		p.Moves[i].RestorePP(p.Moves[i].MaxPP())
+	}
 }
 
-func (p *Pokemon) UseItem() {
-	p.HeldItem.Use(p)
+// MoveLearnRequest asks the caller whether to forget a move so Pokemon can learn Move.
+type MoveLearnRequest struct {
+	Pokemon *Pokemon
+	Move    Move
 }
 
-func GenerateRandomIVs() Stats {
-	return Stats{
-		HP:             rand.Intn(32),
-		Attack:         rand.Intn(32),
-		Defense:        rand.Intn(32),
-		SpecialAttack:  rand.Intn(32),
-		SpecialDefense: rand.Intn(32),
-		Speed:          rand.Intn(32),
+// MoveLearnDecider answers a MoveLearnRequest with the slot to overwrite, or false to skip the move.
+type MoveLearnDecider func(req MoveLearnRequest) (slot int, learn bool)
+
+// Replace forgets the move in slot and learns the requested move in its place.
+func (r MoveLearnRequest) Replace(slot int) error {
+	if slot < 0 || slot >= len(r.Pokemon.Moves) {
+		return fmt.Errorf("invalid move slot %d", slot)
 	}
+	r.Pokemon.Moves[slot] = NewMoveSlot(r.Move)
+	return nil
 }
 
-type StatusEffect interface {
-	Apply(p *Pokemon) bool // true if its still active
-	Name() string
+// Resolve asks decide what to do and carries it out. A nil decider skips the move.
+func (r MoveLearnRequest) Resolve(decide MoveLearnDecider) error {
+	if decide == nil {
+		return nil
+	}
+	if slot, learn := decide(r); learn {
+		return r.Replace(slot)
+	}
+	return nil
 }
 
-type StatusEffectManager struct {
-	Primary   StatusEffect
-	Secondary []StatusEffect
-}
+func (p *Pokemon) Heal(item Item) error {
+	// a fainted pokemon can only be revived, any other item is left unused
+	if reviveItem, ok := item.(ReviveItem); ok && p.Health.IsFainted() {
+		reviveItem.Revive(p)
+		return nil
+	}
+	if err := p.canHeal(item); err != nil {
+		return err
+	}
+	item.Use(p)
 
-func (m *StatusEffectManager) PrimaryStatus() string {
-	return m.Primary.Name()
+	return nil
 }
 
-func (m *StatusEffectManager) UpdateStatusEffects(p *Pokemon) {
-	if m.Primary != nil && !m.Primary.Apply(p) {
-		m.Primary = nil
+// canHeal returns why item would have no effect on p, or nil if it would heal it.
+func (p *Pokemon) canHeal(item Item) error {
+	if p.Health.IsFainted() {
+		return fmt.Errorf("Cannot heal a fainted pokemon with %s", item.Name())
+	}
+	if p.Health.Current >= p.Health.Max {
+		return fmt.Errorf("Cannot heal a pokemon with full health")
 	}
+	return nil
+}
 
-	for i := len(m.Secondary) - 1; i >= 0; i-- {
-		if !m.Secondary[i].Apply(p) {
-			m.Secondary = append(m.Secondary[:i], m.Secondary[i+1:]...) // remove inactive secondary status effects
+// ApplyItem uses item on p: a MoveItem on the move in moveSlot, a Revive on a fainted
+// Pokemon and a healing item on a hurt one. It returns an error, leaving the item
+// unused, when it would have no effect.
+func (p *Pokemon) ApplyItem(item Item, moveSlot int) error {
+	switch item := item.(type) {
+	case MoveItem:
+		if !item.UseOnMove(p, moveSlot) {
+			return fmt.Errorf("%s had no effect on %s", item.Name(), p.Name())
+		}
+		return nil
+	case ReviveItem:
+		if !p.Health.IsFainted() {
+			return fmt.Errorf("%s had no effect on %s", item.Name(), p.Name())
 		}
+		return p.Heal(item)
+	case *Berry:
+		// a berry from the bag isn't the one p holds, so only its effect applies
+		if err := p.canHeal(item); err != nil {
+			return err
+		}
+		item.effect(p)
+		return nil
 	}
+	return fmt.Errorf("%s can't be used on %s", item.Name(), p.Name())
 }
 
-type FaintedStatus struct{}
+func (p *Pokemon) TakeDamage(amount int) {
+	p.Health.decrease(amount)
 
-func (f *FaintedStatus) Apply(p *Pokemon) bool {
-	// Fainted status remains until removed by special item or being healed by a healer.
-	return true
+	if p.Health.IsFainted() {
+		p.StatusManager.Primary = &FaintedStatus{}
+	}
 }
 
-func (f *FaintedStatus) Name() string {
-	return "Fainted"
+// Name is what battle messages call the Pokemon.
+func (p *Pokemon) Name() string {
+	if p.Species == nil {
+		return "Pokemon"
+	}
+	return p.Species.Name
 }
 
-type SleepStatus struct {
-	Duration int
+// HasItem reports whether p is holding item.
+func (p *Pokemon) HasItem(item Item) bool {
+	return p.HeldItem != nil && SameItem(p.HeldItem, item)
 }
 
-func (s *SleepStatus) Apply(p *Pokemon) bool {
-	s.Duration--
-	return s.Duration > 0
+func (p *Pokemon) UseItem() {
+	p.HeldItem.Use(p)
 }
 
-func (s *SleepStatus) Name() string {
-	return "Sleep"
+func GenerateRandomIVs() Stats {
+	return Stats{
+		HP:             rng.Intn(32),
+		Attack:         rng.Intn(32),
+		Defense:        rng.Intn(32),
+		SpecialAttack:  rng.Intn(32),
+		SpecialDefense: rng.Intn(32),
+		Speed:          rng.Intn(32),
+	}
 }
 
-// PoisonEffect to implement the StatusEffect interface for poison
-type PoisonEffect struct {
-	Chance int // Probability of poisoning the target, represented as a percentage
+const (
+	MaxEVPerStat  = 252
+	MaxTotalEVs   = 510
+	MaxFriendship = 255
+)
+
+func (p *Pokemon) EVs() Stats {
+	return p.evs
 }
 
-func (p *PoisonEffect) Apply(target *Pokemon) bool {
-	// Generate a random number to determine if the poison effect is applied
-	if rand.Intn(100) < p.Chance {
-		// Inflict poison status if not already poisoned
-		if target.StatusManager.Primary == nil || target.StatusManager.Primary.Name() != "Poison" {
-			target.StatusManager.Primary = p
-		}
+func (p *Pokemon) totalEVs() int {
+	total := 0
+	for stat := StatHP; stat <= StatSpeed; stat++ {
+		total += p.evs.Get(stat)
 	}
-	// The poison effect persists until cured
-	return true
+	return total
 }
 
-func (p *PoisonEffect) Name() string {
-	return "Poison"
+// AddEVs adds effort values, respecting the per-stat and total caps, and returns what
+// was actually gained. Negative values lower EVs, down to zero.
+func (p *Pokemon) AddEVs(gain Stats) Stats {
+	var gained Stats
+	for stat := StatHP; stat <= StatSpeed; stat++ {
+		current := p.evs.Get(stat)
+		amount := gain.Get(stat)
+		if amount > 0 {
+			amount = min(amount, MaxEVPerStat-current, MaxTotalEVs-p.totalEVs())
+		} else {
+			amount = max(amount, -current)
+		}
+		p.evs.Set(stat, current+amount)
+		gained.Set(stat, amount)
+	}
+
+	if gained != (Stats{}) {
+		p.RecalculateStats()
+	}
+	return gained
 }
 
-// Redo this with IVS accounted for.
-func CalculateStats(stats Stats, level int, ivs Stats) Stats {
-	calc_stat := func(base int, iv int) int {
-		return (2*base+iv)*level/100 + 5
+// RecalculateStats derives Stats again from the species' base stats, keeping the
+// damage the Pokemon has taken the same.
+func (p *Pokemon) RecalculateStats() {
+	oldMaxHealth := p.Health.Max
+	p.Stats = CalculateStats(p.Species.BaseStats, p.Level, p.ivs, p.evs, p.Nature)
+	p.Health.Max = p.Stats.HP
 
+	if !p.Health.IsFainted() {
+		p.Health.Current += p.Health.Max - oldMaxHealth
+		if p.Health.Current < 1 {
+			p.Health.Current = 1
+		}
+		if p.Health.Current > p.Health.Max {
+			p.Health.Current = p.Health.Max
+		}
 	}
-	stats.HP = (2*stats.HP+ivs.HP)*level/100 + level + 10
-	stats.Attack = calc_stat(stats.Attack, ivs.Attack)
-	stats.Defense = calc_stat(stats.Defense, ivs.Defense)
-	stats.SpecialAttack = calc_stat(stats.SpecialAttack, ivs.SpecialAttack)
-	stats.SpecialDefense = calc_stat(stats.SpecialDefense, ivs.SpecialDefense)
-	stats.Speed = calc_stat(stats.Speed, ivs.Speed)
+}
+
+// CalculateStats derives actual stats from base stats, IVs, EVs and level, with the
+// nature's 10% boost and drop applied. A nil nature is neutral.
+func CalculateStats(stats Stats, level int, ivs Stats, evs Stats, nature *Nature) Stats {
+	calc_stat := func(stat Stat) int {
+		value := (2*stats.Get(stat)+ivs.Get(stat)+evs.Get(stat)/4)*level/100 + 5
+		if nature != nil {
+			value = value * nature.percent(stat) / 100
+		}
+		return value
+	}
+	stats.HP = (2*stats.HP+ivs.HP+evs.HP/4)*level/100 + level + 10
+	stats.Attack = calc_stat(StatAttack)
+	stats.Defense = calc_stat(StatDefense)
+	stats.SpecialAttack = calc_stat(StatSpecialAttack)
+	stats.SpecialDefense = calc_stat(StatSpecialDefense)
+	stats.Speed = calc_stat(StatSpeed)
 
 	return stats
 }
 
+// NewPokemon creates a Pokemon with random IVs and full PP. A nil nature picks a random one.
 func NewPokemon(species *Species, level int, heldItem Item, nature *Nature, moves [4]Move) *Pokemon {
+	if nature == nil {
+		randomNature := RandomNature()
+		nature = &randomNature
+	}
 	ivs := GenerateRandomIVs()
-	stats := CalculateStats(species.BaseStats, level, ivs)
+	stats := CalculateStats(species.BaseStats, level, ivs, Stats{}, nature)
 	pokemon := Pokemon{
-		Species:   species,
-		Health:    *NewHealth(stats.HP),
-		Level:     level,
-		HeldItem:  heldItem,
-		Nature:    nature,
-		Moves:     moves,
-		ivs:       ivs,
-		Stats:     stats,
-		Modifiers: StatModifiers{AttackMultiplier: 1.0, DefenseMultplier: 1.0, SpecialAttackMultiplier: 1.0, SpecialDefenseMultiplier: 1.0, SpeedMultiplier: 1.0},
+		Species:    species,
+		Health:     *NewHealth(stats.HP),
+		Level:      level,
+		Experience: species.GrowthRate.ExperienceForLevel(level),
+		HeldItem:   heldItem,
+		Nature:     nature,
+		Moves:      NewMoveSlots(moves),
+		ivs:        ivs,
+		Stats:      stats,
 	}
 	return &pokemon
 }
+
+// NewMoveSlots puts each move in a slot with full PP, leaving empty moves empty.
+func NewMoveSlots(moves [4]Move) [4]MoveSlot {
+	var slots [4]MoveSlot
+	for i, move := range moves {
+		slots[i] = NewMoveSlot(move)
+	}
+	return slots
+}
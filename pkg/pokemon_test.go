@@ -1,7 +1,6 @@
 package pokemon
 
 import (
-	"math/rand"
 	"reflect"
 	"testing"
 )
@@ -25,29 +24,39 @@ func (m *MockRand) Intn(n int) int {
 	return m.IntnFunc(n)
 }
 
+// setRandomizer swaps the package randomizer for the duration of the test.
+func setRandomizer(t *testing.T, r Randomizer) {
+	old := rng
+	rng = r
+	t.Cleanup(func() { rng = old })
+}
+
+// highRolls always returns the highest possible value: moves hit, crits never happen and damage rolls are 100%.
+var highRolls = &MockRand{IntnFunc: func(n int) int { return n - 1 }}
+
 // HelperFunc
 func newPoisonMove() Move {
 	return Move{
-		Name:         "Poison Sting",
-		Type:         Poison,
-		Category:     Physical,
-		Power:        15,
-		Accuracy:     100,
-		PP:           35,
-		StatusEffect: &PoisonEffect{Chance: 30},
+		Name:     "Poison Sting",
+		Type:     Poison,
+		Category: Physical,
+		Power:    15,
+		Accuracy: 100,
+		PP:       35,
+		Effects:  []Effect{SecondaryEffect{Status: "poison", Chance: 30}.Apply},
 	}
 }
 
 // Helper function to create a move that causes sleep
 func NewSleepMove() Move {
 	return Move{
-		Name:         "Sleep Powder",
-		Type:         Grass,
-		Category:     Special,
-		Power:        0,
-		Accuracy:     75,
-		PP:           15,
-		StatusEffect: &SleepStatus{Duration: rand.Intn(3) + 1},
+		Name:     "Sleep Powder",
+		Type:     Grass,
+		Category: Special,
+		Power:    0,
+		Accuracy: 75,
+		PP:       15,
+		Effects:  []Effect{SecondaryEffect{Status: "sleep"}.Apply},
 	}
 }
 
@@ -97,11 +106,202 @@ func TestCalculateStats(t *testing.T) {
 
 	expectedStats := Stats{HP: 110, Attack: 75, Defense: 60, SpecialAttack: 70, SpecialDefense: 70, Speed: 110}
 
-	calculatedStats := CalculateStats(stats, level, ivs)
+	calculatedStats := CalculateStats(stats, level, ivs, Stats{}, nil)
 
 	if !reflect.DeepEqual(calculatedStats, expectedStats) {
 		t.Errorf("Calculatestats() stats = %v, expected = %v", calculatedStats, expectedStats)
 	}
+
+	// Modest raises Special Attack and lowers Attack
+	modest := Modest
+	expectedStats = Stats{HP: 110, Attack: 67, Defense: 60, SpecialAttack: 77, SpecialDefense: 70, Speed: 110}
+	if calculatedStats := CalculateStats(stats, level, ivs, Stats{}, &modest); !reflect.DeepEqual(calculatedStats, expectedStats) {
+		t.Errorf("Calculatestats() with Modest nature stats = %v, expected = %v", calculatedStats, expectedStats)
+	}
+
+	// Neutral natures change nothing
+	hardy := Hardy
+	expectedStats = Stats{HP: 110, Attack: 75, Defense: 60, SpecialAttack: 70, SpecialDefense: 70, Speed: 110}
+	if calculatedStats := CalculateStats(stats, level, ivs, Stats{}, &hardy); !reflect.DeepEqual(calculatedStats, expectedStats) {
+		t.Errorf("Calculatestats() with Hardy nature stats = %v, expected = %v", calculatedStats, expectedStats)
+	}
+}
+
+func TestLevelUpRecalculatesFromBaseStats(t *testing.T) {
+	adamant := Adamant
+	species := &Species{Name: "Machop", BaseStats: Stats{HP: 70, Attack: 80, Defense: 50, SpecialAttack: 35, SpecialDefense: 35, Speed: 35}}
+	machop := NewPokemon(species, 1, nil, &adamant, [4]Move{})
+	machop.AddEVs(Stats{Attack: 252, HP: 100})
+
+	for level := 2; level <= 100; level++ {
+		machop.LevelUp()
+
+		want := CalculateStats(species.BaseStats, level, machop.ivs, machop.EVs(), &adamant)
+		if machop.Stats != want {
+			t.Fatalf("LevelUp() to %d stats = %v, want %v", level, machop.Stats, want)
+		}
+		if machop.Health.Max != want.HP || machop.Health.Current != want.HP {
+			t.Fatalf("LevelUp() to %d health = %v, want full at %d", level, machop.Health, want.HP)
+		}
+	}
+}
+
+func TestLevelUpKeepsDamageTaken(t *testing.T) {
+	species := &Species{Name: "Machop", BaseStats: Stats{HP: 70, Attack: 80, Defense: 50, SpecialAttack: 35, SpecialDefense: 35, Speed: 35}}
+	machop := NewPokemon(species, 20, nil, nil, [4]Move{})
+	machop.TakeDamage(15)
+
+	machop.LevelUp()
+
+	if machop.Health.Current != machop.Health.Max-15 {
+		t.Errorf("LevelUp() health = %v, want 15 below max", machop.Health)
+	}
+}
+
+func TestEvolveRecalculatesStats(t *testing.T) {
+	calm := Calm
+	pokedex := NewPokedex([]Species{
+		{ID: 1, Name: "Bulbasaur", BaseStats: Stats{HP: 45, Attack: 49, Defense: 49, SpecialAttack: 65, SpecialDefense: 65, Speed: 45},
+			EvolutionStages: []EvolutionStage{NewEvolutionStage(2, LevelEvolution{RequiredLevel: 16})}},
+		{ID: 2, Name: "Ivysaur", BaseStats: Stats{HP: 60, Attack: 62, Defense: 63, SpecialAttack: 80, SpecialDefense: 80, Speed: 60}},
+	})
+	bulbasaur := NewPokemon(pokedex.GetSpeciesByID(1), 16, nil, &calm, [4]Move{})
+
+	if _, err := bulbasaur.Evolve(pokedex, EvolutionContext{Time: "Day"}); err != nil {
+		t.Fatalf("Evolve() error %v", err)
+	}
+
+	want := CalculateStats(pokedex.GetSpeciesByID(2).BaseStats, 16, bulbasaur.ivs, bulbasaur.EVs(), &calm)
+	if bulbasaur.Stats != want {
+		t.Errorf("Evolve() stats = %v, want %v", bulbasaur.Stats, want)
+	}
+	if bulbasaur.Health.Max != want.HP {
+		t.Errorf("Evolve() max health = %d, want %d", bulbasaur.Health.Max, want.HP)
+	}
+}
+
+func newLearnsetSpecies() *Species {
+	return &Species{
+		Name:      "Charmander",
+		BaseStats: Stats{HP: 39, Attack: 52, Defense: 43, SpecialAttack: 60, SpecialDefense: 50, Speed: 65},
+		Learnset: Learnset{
+			{Level: 1, Move: Move{Name: "Scratch"}},
+			{Level: 1, Move: Move{Name: "Growl"}},
+			{Level: 4, Move: Move{Name: "Ember"}},
+			{Level: 8, Move: Move{Name: "Smokescreen"}},
+			{Level: 12, Move: Move{Name: "Dragon Breath"}},
+			{Level: 12, Move: Move{Name: "Fire Fang"}},
+		},
+	}
+}
+
+func TestLevelUpLearnsMoves(t *testing.T) {
+	species := newLearnsetSpecies()
+	charmander := NewPokemon(species, 3, nil, nil, [4]Move{{Name: "Scratch"}, {Name: "Growl"}})
+
+	result := charmander.LevelUp()
+	if result.Level != 4 || len(result.LearnedMoves) != 1 || result.LearnedMoves[0].Name != "Ember" {
+		t.Errorf("LevelUp() to 4 = %+v, want Ember learned", result)
+	}
+	if charmander.Moves[2].Move.Name != "Ember" {
+		t.Errorf("Expected Ember in the third slot, got %v", charmander.Moves[2].Move.Name)
+	}
+
+	for charmander.Level < 11 {
+		charmander.LevelUp()
+	}
+
+	// Both level 12 moves show up, but only one slot is left
+	result = charmander.LevelUp()
+	if len(result.LearnedMoves) != 0 || len(result.PendingMoves) != 2 {
+		t.Fatalf("LevelUp() to 12 learned %d and left %d pending, want 0 and 2", len(result.LearnedMoves), len(result.PendingMoves))
+	}
+
+	forgetGrowl := func(req MoveLearnRequest) (int, bool) { return 1, req.Move.Name == "Fire Fang" }
+	for _, req := range result.PendingMoves {
+		if err := req.Resolve(forgetGrowl); err != nil {
+			t.Fatalf("Resolve() error %v", err)
+		}
+	}
+
+	want := [4]string{"Scratch", "Fire Fang", "Ember", "Smokescreen"}
+	for i, move := range charmander.Moves {
+		if move.Move.Name != want[i] {
+			t.Errorf("Move slot %d = %q, want %q", i, move.Move.Name, want[i])
+		}
+	}
+}
+
+func TestMoveLearnRequestReplace(t *testing.T) {
+	charmander := NewPokemon(newLearnsetSpecies(), 5, nil, nil, [4]Move{{Name: "Scratch"}})
+	req := MoveLearnRequest{Pokemon: charmander, Move: Move{Name: "Ember"}}
+
+	if err := req.Replace(4); err == nil {
+		t.Errorf("Expected error replacing slot 4, got nil")
+	}
+	if err := req.Resolve(nil); err != nil || charmander.KnowsMove("Ember") {
+		t.Errorf("Resolve() without a decider should skip the move")
+	}
+}
+
+func TestCalculateStatsWithEVs(t *testing.T) {
+	base := Stats{HP: 100, Attack: 100, Defense: 100, SpecialAttack: 100, SpecialDefense: 100, Speed: 100}
+	ivs := Stats{HP: 31, Attack: 31, Defense: 31, SpecialAttack: 31, SpecialDefense: 31, Speed: 31}
+	evs := Stats{HP: 252, Attack: 252, Speed: 6}
+
+	expectedStats := Stats{HP: 404, Attack: 299, Defense: 236, SpecialAttack: 236, SpecialDefense: 236, Speed: 237}
+	if calculatedStats := CalculateStats(base, 100, ivs, evs, nil); calculatedStats != expectedStats {
+		t.Errorf("CalculateStats() stats = %v, expected = %v", calculatedStats, expectedStats)
+	}
+}
+
+func TestAddEVs(t *testing.T) {
+	hardy := Hardy
+	species := &Species{Name: "Snorlax", BaseStats: Stats{HP: 160, Attack: 110, Defense: 65, SpecialAttack: 65, SpecialDefense: 110, Speed: 30}}
+	snorlax := NewPokemon(species, 50, nil, &hardy, [4]Move{})
+	maxHealth := snorlax.Health.Max
+
+	gained := snorlax.AddEVs(Stats{HP: 300, Attack: 100})
+	if want := (Stats{HP: 252, Attack: 100}); gained != want {
+		t.Errorf("AddEVs() gained = %v, want %v", gained, want)
+	}
+	if snorlax.Health.Max <= maxHealth || snorlax.Health.Current != snorlax.Health.Max {
+		t.Errorf("AddEVs() should raise max health, got %v from %d", snorlax.Health, maxHealth)
+	}
+	if want := CalculateStats(species.BaseStats, 50, snorlax.ivs, snorlax.EVs(), &hardy); snorlax.Stats != want {
+		t.Errorf("AddEVs() stats = %v, want %v", snorlax.Stats, want)
+	}
+
+	// Only 158 left before the 510 total cap
+	gained = snorlax.AddEVs(Stats{Defense: 200})
+	if gained.Defense != 158 || snorlax.totalEVs() != MaxTotalEVs {
+		t.Errorf("AddEVs() gained %d Defense, total %d, want 158 and %d", gained.Defense, snorlax.totalEVs(), MaxTotalEVs)
+	}
+
+	gained = snorlax.AddEVs(Stats{Speed: 4})
+	if gained != (Stats{}) {
+		t.Errorf("AddEVs() past the total cap gained %v", gained)
+	}
+
+	gained = snorlax.AddEVs(Stats{Attack: -150})
+	if gained.Attack != -100 || snorlax.EVs().Attack != 0 {
+		t.Errorf("AddEVs() lowering Attack gained %d, left %d, want -100 and 0", gained.Attack, snorlax.EVs().Attack)
+	}
+}
+
+func TestNewPokemonNature(t *testing.T) {
+	species := &Species{Name: "Pikachu", BaseStats: Stats{HP: 35, Attack: 55, Defense: 40, SpecialAttack: 50, SpecialDefense: 50, Speed: 90}}
+
+	random := NewPokemon(species, 5, nil, nil, [4]Move{})
+	if random.Nature == nil {
+		t.Fatalf("NewPokemon() without a nature should pick a random one")
+	}
+
+	jolly := Jolly
+	pikachu := NewPokemon(species, 50, nil, &jolly, [4]Move{})
+	if want := CalculateStats(species.BaseStats, 50, pikachu.ivs, Stats{}, &jolly); pikachu.Stats != want {
+		t.Errorf("NewPokemon() stats = %v, want %v", pikachu.Stats, want)
+	}
 }
 
 // Test the execution of a move with a chance to poison
@@ -136,14 +336,14 @@ func TestMoveExecuteWithSleep(t *testing.T) {
 	}
 }
 
-// Test the update of status effects at the start of each turn
-func TestUpdateStatusEffects(t *testing.T) {
+// Test the update of status effects at the end of each turn
+func TestStatusTurnEnd(t *testing.T) {
 	pokemon := NewPokemon(CharmanderSpecies, 50, nil, nil, [4]Move{})
 	pokemon.StatusManager.Primary = &SleepStatus{Duration: 2}
 
 	// Simulate turns
 	for i := 0; i < 3; i++ {
-		pokemon.StatusManager.UpdateStatusEffects(pokemon)
+		pokemon.StatusManager.TurnEnd(pokemon, nil)
 		if pokemon.StatusManager.Primary != nil && pokemon.StatusManager.Primary.Name() == "Sleep" {
 			t.Logf("Charmander is asleep. Turns left: %d", 2-i)
 		} else {
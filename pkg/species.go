@@ -6,7 +6,7 @@ type Time string
 type Weather string
 
 type EvolutionMethod interface {
-	CanEvolve(p *Pokemon, currentTime Time, currentWeather Weather, currentLocation string) bool
+	CanEvolve(p *Pokemon, ctx EvolutionContext) bool
 }
 
 type EvolutionStage struct {
@@ -25,8 +25,8 @@ type LevelEvolution struct {
 	RequiredLevel int
 }
 
-func (l LevelEvolution) CanEvolve(p *Pokemon, _ Time, _ Weather, _ string) bool {
-	return p.Level >= l.RequiredLevel
+func (l LevelEvolution) CanEvolve(p *Pokemon, ctx EvolutionContext) bool {
+	return ctx.Trigger == LevelUpTrigger && p.Level >= l.RequiredLevel
 }
 
 type FriendshipEvolution struct {
@@ -34,16 +34,223 @@ type FriendshipEvolution struct {
 	RequiredTime       Time
 }
 
-func (f FriendshipEvolution) CanEvolve(p *Pokemon, currentTime Time, _ Weather, _ string) bool {
-	return p.Friendship >= f.RequiredFriendship && currentTime == f.RequiredTime
+// FriendshipEvolution evolves on level up with enough friendship. An empty RequiredTime means any time of day.
+func (f FriendshipEvolution) CanEvolve(p *Pokemon, ctx EvolutionContext) bool {
+	return ctx.Trigger == LevelUpTrigger && p.Friendship >= f.RequiredFriendship &&
+		(f.RequiredTime == "" || ctx.Time == f.RequiredTime)
 }
 
+// ItemConsumer is implemented by evolution methods that use up an item when the Pokemon evolves.
+type ItemConsumer interface {
+	ConsumeItem(p *Pokemon, ctx EvolutionContext) Item
+}
+
+// ItemEvolution evolves when an item such as an evolution stone is used from the bag.
 type ItemEvolution struct {
 	RequiredItem Item
 }
 
-func (i ItemEvolution) CanEvolve(p *Pokemon, _ Time, _ Weather, _ string) bool {
-	return p.HasItem(i.RequiredItem)
+func (i ItemEvolution) CanEvolve(_ *Pokemon, ctx EvolutionContext) bool {
+	return ctx.Trigger == ItemTrigger && SameItem(ctx.Item, i.RequiredItem)
+}
+
+// ConsumeItem returns the used item, taking it out of the bag is up to the caller.
+func (i ItemEvolution) ConsumeItem(_ *Pokemon, ctx EvolutionContext) Item {
+	return ctx.Item
+}
+
+// HeldItemEvolution evolves on level up while holding an item, optionally at a set time of day.
+type HeldItemEvolution struct {
+	RequiredItem Item
+	RequiredTime Time
+}
+
+func (h HeldItemEvolution) CanEvolve(p *Pokemon, ctx EvolutionContext) bool {
+	return ctx.Trigger == LevelUpTrigger && p.HasItem(h.RequiredItem) &&
+		(h.RequiredTime == "" || ctx.Time == h.RequiredTime)
+}
+
+func (h HeldItemEvolution) ConsumeItem(p *Pokemon, _ EvolutionContext) Item {
+	item := p.HeldItem
+	p.HeldItem = nil
+	return item
+}
+
+// TradeEvolution evolves when traded, optionally only while holding HeldItem or when
+// traded for a specific species.
+type TradeEvolution struct {
+	HeldItem   Item
+	TradedWith int // species ID, 0 for any
+}
+
+func (t TradeEvolution) CanEvolve(p *Pokemon, ctx EvolutionContext) bool {
+	if ctx.Trigger != TradeTrigger {
+		return false
+	}
+	if t.HeldItem != nil && !p.HasItem(t.HeldItem) {
+		return false
+	}
+	return t.TradedWith == 0 || (ctx.TradedWith != nil && ctx.TradedWith.ID == t.TradedWith)
+}
+
+func (t TradeEvolution) ConsumeItem(p *Pokemon, _ EvolutionContext) Item {
+	if t.HeldItem == nil {
+		return nil
+	}
+	item := p.HeldItem
+	p.HeldItem = nil
+	return item
+}
+
+// LocationEvolution evolves on level up at a specific location.
+type LocationEvolution struct {
+	Location string
+}
+
+func (l LocationEvolution) CanEvolve(_ *Pokemon, ctx EvolutionContext) bool {
+	return ctx.Trigger == LevelUpTrigger && ctx.Location == l.Location
+}
+
+// WeatherEvolution evolves on level up from RequiredLevel while the weather is right.
+type WeatherEvolution struct {
+	Weather       Weather
+	RequiredLevel int
+}
+
+func (w WeatherEvolution) CanEvolve(p *Pokemon, ctx EvolutionContext) bool {
+	return ctx.Trigger == LevelUpTrigger && p.Level >= w.RequiredLevel && ctx.Weather == w.Weather
+}
+
+// KnowsMoveEvolution evolves on level up while knowing a move.
+type KnowsMoveEvolution struct {
+	MoveName string
+}
+
+func (k KnowsMoveEvolution) CanEvolve(p *Pokemon, ctx EvolutionContext) bool {
+	return ctx.Trigger == LevelUpTrigger && p.KnowsMove(k.MoveName)
+}
+
+// PartySpeciesEvolution evolves on level up while another party member is of the given species.
+type PartySpeciesEvolution struct {
+	SpeciesID     int
+	RequiredLevel int
+}
+
+func (ps PartySpeciesEvolution) CanEvolve(p *Pokemon, ctx EvolutionContext) bool {
+	if ctx.Trigger != LevelUpTrigger || p.Level < ps.RequiredLevel {
+		return false
+	}
+	for _, member := range ctx.Party {
+		if member != nil && member != p && member.Species != nil && member.Species.ID == ps.SpeciesID {
+			return true
+		}
+	}
+	return false
+}
+
+// GenderEvolution evolves for one gender only, either on level up from RequiredLevel
+// or, when RequiredItem is set, by using that item.
+type GenderEvolution struct {
+	Gender        Gender
+	RequiredLevel int
+	RequiredItem  Item
+}
+
+func (g GenderEvolution) CanEvolve(p *Pokemon, ctx EvolutionContext) bool {
+	if p.Gender != g.Gender {
+		return false
+	}
+	if g.RequiredItem != nil {
+		return ItemEvolution{RequiredItem: g.RequiredItem}.CanEvolve(p, ctx)
+	}
+	return LevelEvolution{RequiredLevel: g.RequiredLevel}.CanEvolve(p, ctx)
+}
+
+func (g GenderEvolution) ConsumeItem(_ *Pokemon, ctx EvolutionContext) Item {
+	if g.RequiredItem == nil {
+		return nil
+	}
+	return ctx.Item
+}
+
+type StatComparison int
+
+const (
+	AttackHigher StatComparison = iota
+	DefenseHigher
+	AttackDefenseEqual
+)
+
+// StatComparisonEvolution evolves on level up from RequiredLevel depending on how Attack compares to Defense.
+type StatComparisonEvolution struct {
+	RequiredLevel int
+	Comparison    StatComparison
+}
+
+func (sc StatComparisonEvolution) CanEvolve(p *Pokemon, ctx EvolutionContext) bool {
+	if ctx.Trigger != LevelUpTrigger || p.Level < sc.RequiredLevel {
+		return false
+	}
+	switch sc.Comparison {
+	case AttackHigher:
+		return p.Stats.Attack > p.Stats.Defense
+	case DefenseHigher:
+		return p.Stats.Attack < p.Stats.Defense
+	default:
+		return p.Stats.Attack == p.Stats.Defense
+	}
+}
+
+// AndEvolution evolves when all of its methods allow it.
+type AndEvolution struct {
+	Methods []EvolutionMethod
+}
+
+func (a AndEvolution) CanEvolve(p *Pokemon, ctx EvolutionContext) bool {
+	for _, method := range a.Methods {
+		if !method.CanEvolve(p, ctx) {
+			return false
+		}
+	}
+	return len(a.Methods) > 0
+}
+
+func (a AndEvolution) ConsumeItem(p *Pokemon, ctx EvolutionContext) Item {
+	return consumeFirstItem(a.Methods, p, ctx)
+}
+
+// OrEvolution evolves when any of its methods allow it.
+type OrEvolution struct {
+	Methods []EvolutionMethod
+}
+
+func (o OrEvolution) CanEvolve(p *Pokemon, ctx EvolutionContext) bool {
+	for _, method := range o.Methods {
+		if method.CanEvolve(p, ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o OrEvolution) ConsumeItem(p *Pokemon, ctx EvolutionContext) Item {
+	for _, method := range o.Methods {
+		if method.CanEvolve(p, ctx) {
+			return consumeFirstItem([]EvolutionMethod{method}, p, ctx)
+		}
+	}
+	return nil
+}
+
+func consumeFirstItem(methods []EvolutionMethod, p *Pokemon, ctx EvolutionContext) Item {
+	for _, method := range methods {
+		if consumer, ok := method.(ItemConsumer); ok {
+			if item := consumer.ConsumeItem(p, ctx); item != nil {
+				return item
+			}
+		}
+	}
+	return nil
 }
 
 type Species struct {
@@ -52,24 +259,72 @@ type Species struct {
 	Types           []Type
 	BaseStats       Stats
 	BaseExpYield    int
+	GrowthRate      GrowthRate
+	EVYield         Stats
+	CaptureRate     int `json:",omitempty"` // out of 255, higher is easier to catch
 	EvolutionStages []EvolutionStage
-	Learnset        map[int]Move
+	Learnset        Learnset
+}
+
+// LearnableMove is a move a species learns on reaching Level.
+type LearnableMove struct {
+	Level int
+	Move  Move
 }
 
+type Learnset []LearnableMove
 
-func (p *Pokemon) Evolve(pokedex PokedexRepository, currentTime Time, currentWeather Weather, currentLocation string) error {
+// MovesAt returns the moves learned at exactly level, in learnset order.
+func (l Learnset) MovesAt(level int) []Move {
+	var moves []Move
+	for _, entry := range l {
+		if entry.Level == level {
+			moves = append(moves, entry.Move)
+		}
+	}
+	return moves
+}
+
+
+// FindEvolution returns the species p would evolve into under ctx, or nil if no
+// evolution stage allows it.
+func (p *Pokemon) FindEvolution(pokedex PokedexRepository, ctx EvolutionContext) (*Species, error) {
+	_, newSpecies, err := p.findEvolution(pokedex, ctx)
+	return newSpecies, err
+}
+
+func (p *Pokemon) findEvolution(pokedex PokedexRepository, ctx EvolutionContext) (EvolutionStage, *Species, error) {
 	for _, stage := range p.Species.EvolutionStages {
-		if stage.Method.CanEvolve(p, currentTime, currentWeather, currentLocation) {
+		if stage.Method.CanEvolve(p, ctx) {
 			newSpecies := pokedex.GetSpeciesByID(stage.EvolvesInto)
 
 			if newSpecies == nil {
-				return fmt.Errorf("Species with ID %d does not exist", stage.EvolvesInto)
+				return stage, nil, fmt.Errorf("Species with ID %d does not exist", stage.EvolvesInto)
 			}
-
-			fmt.Printf("%s has evolved into %s!\n", p.Species.Name, newSpecies.Name)
-			p.Species = newSpecies
-			return nil
+			return stage, newSpecies, nil
 		}
 	}
-	return fmt.Errorf("no evolution conditions met")
+	return EvolutionStage{}, nil, nil
+}
+
+// Evolve evolves p straight away if any of its evolution stages allow it under ctx.
+func (p *Pokemon) Evolve(pokedex PokedexRepository, ctx EvolutionContext) (*EvolutionEvent, error) {
+	stage, newSpecies, err := p.findEvolution(pokedex, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if newSpecies == nil {
+		return nil, fmt.Errorf("no evolution conditions met")
+	}
+	return p.evolveInto(stage, newSpecies, ctx), nil
+}
+
+func (p *Pokemon) evolveInto(stage EvolutionStage, newSpecies *Species, ctx EvolutionContext) *EvolutionEvent {
+	event := &EvolutionEvent{Pokemon: p, From: p.Species, Into: newSpecies, Trigger: ctx.Trigger}
+	if consumer, ok := stage.Method.(ItemConsumer); ok {
+		event.ConsumedItem = consumer.ConsumeItem(p, ctx)
+	}
+	p.Species = newSpecies
+	p.RecalculateStats()
+	return event
 }
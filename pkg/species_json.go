@@ -0,0 +1,278 @@
+package pokemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Evolution methods are stored in JSON as a tagged union, e.g.
+// {"kind": "level", "data": {"RequiredLevel": 16}}. Items are stored by ID.
+type evolutionMethodJSON struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+var evolutionMethodKinds = map[string]reflect.Type{}
+
+// RegisterEvolutionMethod makes a custom EvolutionMethod serializable under kind.
+func RegisterEvolutionMethod(kind string, method EvolutionMethod) {
+	evolutionMethodKinds[kind] = reflect.TypeOf(method)
+}
+
+func init() {
+	RegisterEvolutionMethod("level", LevelEvolution{})
+	RegisterEvolutionMethod("friendship", FriendshipEvolution{})
+	RegisterEvolutionMethod("item", ItemEvolution{})
+	RegisterEvolutionMethod("held_item", HeldItemEvolution{})
+	RegisterEvolutionMethod("trade", TradeEvolution{})
+	RegisterEvolutionMethod("location", LocationEvolution{})
+	RegisterEvolutionMethod("weather", WeatherEvolution{})
+	RegisterEvolutionMethod("knows_move", KnowsMoveEvolution{})
+	RegisterEvolutionMethod("party_species", PartySpeciesEvolution{})
+	RegisterEvolutionMethod("gender", GenderEvolution{})
+	RegisterEvolutionMethod("stat_comparison", StatComparisonEvolution{})
+	RegisterEvolutionMethod("and", AndEvolution{})
+	RegisterEvolutionMethod("or", OrEvolution{})
+}
+
+func evolutionMethodKind(method EvolutionMethod) (string, bool) {
+	typ := reflect.TypeOf(method)
+	for kind, t := range evolutionMethodKinds {
+		if t == typ {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+func marshalEvolutionMethod(method EvolutionMethod) (*evolutionMethodJSON, error) {
+	if method == nil {
+		return nil, nil
+	}
+	kind, ok := evolutionMethodKind(method)
+	if !ok {
+		return nil, fmt.Errorf("evolution method %T is not registered", method)
+	}
+	data, err := json.Marshal(method)
+	if err != nil {
+		return nil, err
+	}
+	return &evolutionMethodJSON{Kind: kind, Data: data}, nil
+}
+
+func unmarshalEvolutionMethod(m *evolutionMethodJSON) (EvolutionMethod, error) {
+	if m == nil {
+		return nil, nil
+	}
+	typ, ok := evolutionMethodKinds[m.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown evolution method kind %q", m.Kind)
+	}
+	method := reflect.New(typ)
+	if len(m.Data) > 0 {
+		if err := json.Unmarshal(m.Data, method.Interface()); err != nil {
+			return nil, fmt.Errorf("evolution method %q: %w", m.Kind, err)
+		}
+	}
+	return method.Elem().Interface().(EvolutionMethod), nil
+}
+
+func marshalEvolutionMethods(methods []EvolutionMethod) ([]*evolutionMethodJSON, error) {
+	encoded := make([]*evolutionMethodJSON, len(methods))
+	for i, method := range methods {
+		m, err := marshalEvolutionMethod(method)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = m
+	}
+	return encoded, nil
+}
+
+func unmarshalEvolutionMethods(encoded []*evolutionMethodJSON) ([]EvolutionMethod, error) {
+	methods := make([]EvolutionMethod, len(encoded))
+	for i, m := range encoded {
+		method, err := unmarshalEvolutionMethod(m)
+		if err != nil {
+			return nil, err
+		}
+		methods[i] = method
+	}
+	return methods, nil
+}
+
+func (s EvolutionStage) MarshalJSON() ([]byte, error) {
+	method, err := marshalEvolutionMethod(s.Method)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		EvolvesInto int
+		Method      *evolutionMethodJSON
+	}{s.EvolvesInto, method})
+}
+
+func (s *EvolutionStage) UnmarshalJSON(data []byte) error {
+	var stage struct {
+		EvolvesInto int
+		Method      *evolutionMethodJSON
+	}
+	if err := json.Unmarshal(data, &stage); err != nil {
+		return err
+	}
+	method, err := unmarshalEvolutionMethod(stage.Method)
+	if err != nil {
+		return err
+	}
+	*s = EvolutionStage{EvolvesInto: stage.EvolvesInto, Method: method}
+	return nil
+}
+
+func (a AndEvolution) MarshalJSON() ([]byte, error) {
+	methods, err := marshalEvolutionMethods(a.Methods)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct{ Methods []*evolutionMethodJSON }{methods})
+}
+
+func (a *AndEvolution) UnmarshalJSON(data []byte) error {
+	var encoded struct{ Methods []*evolutionMethodJSON }
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	methods, err := unmarshalEvolutionMethods(encoded.Methods)
+	a.Methods = methods
+	return err
+}
+
+func (o OrEvolution) MarshalJSON() ([]byte, error) {
+	methods, err := marshalEvolutionMethods(o.Methods)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct{ Methods []*evolutionMethodJSON }{methods})
+}
+
+func (o *OrEvolution) UnmarshalJSON(data []byte) error {
+	var encoded struct{ Methods []*evolutionMethodJSON }
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	methods, err := unmarshalEvolutionMethods(encoded.Methods)
+	o.Methods = methods
+	return err
+}
+
+// itemID is the ID stored for item, empty for no item.
+func itemID(item Item) ItemID {
+	if item == nil {
+		return ""
+	}
+	return item.ID()
+}
+
+// itemFromID finds a registered item, an empty ID is no item.
+func itemFromID(id ItemID) (Item, error) {
+	if id == "" {
+		return nil, nil
+	}
+	item, ok := ItemByID(id)
+	if !ok {
+		return nil, fmt.Errorf("unknown item %q", id)
+	}
+	return item, nil
+}
+
+func (i ItemEvolution) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct{ RequiredItem ItemID }{itemID(i.RequiredItem)})
+}
+
+func (i *ItemEvolution) UnmarshalJSON(data []byte) error {
+	var encoded struct{ RequiredItem ItemID }
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	item, err := itemFromID(encoded.RequiredItem)
+	i.RequiredItem = item
+	return err
+}
+
+type heldItemEvolutionJSON struct {
+	RequiredItem ItemID
+	RequiredTime Time `json:",omitempty"`
+}
+
+func (h HeldItemEvolution) MarshalJSON() ([]byte, error) {
+	return json.Marshal(heldItemEvolutionJSON{itemID(h.RequiredItem), h.RequiredTime})
+}
+
+func (h *HeldItemEvolution) UnmarshalJSON(data []byte) error {
+	var encoded heldItemEvolutionJSON
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	item, err := itemFromID(encoded.RequiredItem)
+	*h = HeldItemEvolution{RequiredItem: item, RequiredTime: encoded.RequiredTime}
+	return err
+}
+
+type tradeEvolutionJSON struct {
+	HeldItem   ItemID `json:",omitempty"`
+	TradedWith int    `json:",omitempty"`
+}
+
+func (t TradeEvolution) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tradeEvolutionJSON{itemID(t.HeldItem), t.TradedWith})
+}
+
+func (t *TradeEvolution) UnmarshalJSON(data []byte) error {
+	var encoded tradeEvolutionJSON
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	item, err := itemFromID(encoded.HeldItem)
+	*t = TradeEvolution{HeldItem: item, TradedWith: encoded.TradedWith}
+	return err
+}
+
+type genderEvolutionJSON struct {
+	Gender        Gender
+	RequiredLevel int    `json:",omitempty"`
+	RequiredItem  ItemID `json:",omitempty"`
+}
+
+func (g GenderEvolution) MarshalJSON() ([]byte, error) {
+	return json.Marshal(genderEvolutionJSON{g.Gender, g.RequiredLevel, itemID(g.RequiredItem)})
+}
+
+func (g *GenderEvolution) UnmarshalJSON(data []byte) error {
+	var encoded genderEvolutionJSON
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	item, err := itemFromID(encoded.RequiredItem)
+	*g = GenderEvolution{Gender: encoded.Gender, RequiredLevel: encoded.RequiredLevel, RequiredItem: item}
+	return err
+}
+
+// Learnset moves are stored by name and only hold the name after unmarshalling,
+// Pokedex.ResolveLearnsets fills in the rest from a MoveRegistry.
+type learnableMoveJSON struct {
+	Level int
+	Move  string
+}
+
+func (l LearnableMove) MarshalJSON() ([]byte, error) {
+	return json.Marshal(learnableMoveJSON{l.Level, l.Move.Name})
+}
+
+func (l *LearnableMove) UnmarshalJSON(data []byte) error {
+	var encoded learnableMoveJSON
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	*l = LearnableMove{Level: encoded.Level, Move: Move{Name: encoded.Move}}
+	return nil
+}
@@ -8,6 +8,10 @@ type MockCoat struct {
 	Item
 }
 
+func (MockCoat) ID() ItemID {
+	return "mock-coat"
+}
+
 var (
 	Mockdata  = []Species{
 		{
@@ -27,7 +31,7 @@ var (
 			EvolutionStages: []EvolutionStage{
 				{
 					EvolvesInto: 208, // Steelix's ID
-					Method:      ItemEvolution{RequiredItem: MockCoat{}},
+					Method:      TradeEvolution{HeldItem: MockCoat{}},
 				},
 			},
 		},
@@ -35,6 +39,13 @@ var (
 			ID:   133,
 			Name: "Eevee",
 			EvolutionStages: []EvolutionStage{
+				{
+					EvolvesInto: 700, // Sylveon's ID
+					Method: AndEvolution{Methods: []EvolutionMethod{
+						FriendshipEvolution{RequiredFriendship: 160},
+						KnowsMoveEvolution{MoveName: "Baby-Doll Eyes"},
+					}},
+				},
 				{
 					EvolvesInto: 196, // Espeon's ID
 					Method: FriendshipEvolution{
@@ -51,6 +62,68 @@ var (
 				},
 			},
 		},
+		{
+			ID:   25,
+			Name: "Pikachu",
+			EvolutionStages: []EvolutionStage{
+				{EvolvesInto: 26, Method: ItemEvolution{RequiredItem: ThunderStone}},
+			},
+		},
+		{ID: 26, Name: "Raichu"},
+		{
+			ID:   215,
+			Name: "Sneasel",
+			EvolutionStages: []EvolutionStage{
+				{EvolvesInto: 461, Method: HeldItemEvolution{RequiredItem: RazorClaw, RequiredTime: "Night"}},
+			},
+		},
+		{ID: 461, Name: "Weavile"},
+		{
+			ID:   82,
+			Name: "Magneton",
+			EvolutionStages: []EvolutionStage{
+				{EvolvesInto: 462, Method: OrEvolution{Methods: []EvolutionMethod{
+					LocationEvolution{Location: "Mt. Coronet"},
+					ItemEvolution{RequiredItem: ThunderStone},
+				}}},
+			},
+		},
+		{ID: 462, Name: "Magnezone"},
+		{ID: 588, Name: "Karrablast", EvolutionStages: []EvolutionStage{{EvolvesInto: 589, Method: TradeEvolution{TradedWith: 616}}}},
+		{ID: 589, Name: "Escavalier"},
+		{ID: 616, Name: "Shelmet"},
+		{ID: 705, Name: "Sliggoo", EvolutionStages: []EvolutionStage{{EvolvesInto: 706, Method: WeatherEvolution{Weather: "Rain", RequiredLevel: 50}}}},
+		{ID: 706, Name: "Goodra"},
+		{ID: 221, Name: "Piloswine", EvolutionStages: []EvolutionStage{{EvolvesInto: 473, Method: KnowsMoveEvolution{MoveName: "Ancient Power"}}}},
+		{ID: 473, Name: "Mamoswine"},
+		{ID: 458, Name: "Mantyke", EvolutionStages: []EvolutionStage{{EvolvesInto: 226, Method: PartySpeciesEvolution{SpeciesID: 223}}}},
+		{ID: 226, Name: "Mantine"},
+		{ID: 223, Name: "Remoraid"},
+		{ID: 415, Name: "Combee", EvolutionStages: []EvolutionStage{{EvolvesInto: 416, Method: GenderEvolution{Gender: Female, RequiredLevel: 21}}}},
+		{ID: 416, Name: "Vespiquen"},
+		{
+			ID:   281,
+			Name: "Kirlia",
+			EvolutionStages: []EvolutionStage{
+				{EvolvesInto: 475, Method: GenderEvolution{Gender: Male, RequiredItem: DawnStone}},
+				{EvolvesInto: 282, Method: LevelEvolution{RequiredLevel: 30}},
+			},
+		},
+		{ID: 282, Name: "Gardevoir"},
+		{ID: 475, Name: "Gallade"},
+		{
+			ID:   236,
+			Name: "Tyrogue",
+			EvolutionStages: []EvolutionStage{
+				{EvolvesInto: 106, Method: StatComparisonEvolution{RequiredLevel: 20, Comparison: AttackHigher}},
+				{EvolvesInto: 107, Method: StatComparisonEvolution{RequiredLevel: 20, Comparison: DefenseHigher}},
+				{EvolvesInto: 237, Method: StatComparisonEvolution{RequiredLevel: 20, Comparison: AttackDefenseEqual}},
+			},
+		},
+		{ID: 106, Name: "Hitmonlee"},
+		{ID: 107, Name: "Hitmonchan"},
+		{ID: 237, Name: "Hitmontop"},
+		{ID: 700, Name: "Sylveon"},
 		{ID: 196, Name: "Espeon"},
 
 		{ID: 197, Name: "Umbreon"},
@@ -63,20 +136,31 @@ func TestEvolution(t *testing.T) {
 	tests := []struct {
 		name            string
 		pokemon         *Pokemon
+		trigger         EvolutionTrigger
 		currentTime     Time
 		currentWeather  Weather
 		currentLocation string
 		wantSpeciesID   int
 	}{
-		{"Eevee to Espeon", &Pokemon{Species: pokedex.GetSpeciesByID(133), Level: 20, Friendship: 220}, "Day", "", "", 196},
-		{"Eevee to Umbreon", &Pokemon{Species: pokedex.GetSpeciesByID(133), Level: 20, Friendship: 220}, "Night", "", "", 197},
-		{"Charmeleon to Charizard", &Pokemon{Species: pokedex.GetSpeciesByID(5), Level: 36}, "Anytime", "", "", 6},
-		{"Onix to Steelix with Metal Coat", &Pokemon{Species: pokedex.GetSpeciesByID(95), Level: 25, HeldItem: MockCoat{}}, "Anytime", "", "", 208},
+		{"Eevee to Espeon", &Pokemon{Species: pokedex.GetSpeciesByID(133), Level: 20, Friendship: 220}, LevelUpTrigger, "Day", "", "", 196},
+		{"Eevee to Umbreon", &Pokemon{Species: pokedex.GetSpeciesByID(133), Level: 20, Friendship: 220}, LevelUpTrigger, "Night", "", "", 197},
+		{"Eevee to Sylveon", &Pokemon{Species: pokedex.GetSpeciesByID(133), Level: 20, Friendship: 220, Moves: NewMoveSlots([4]Move{{Name: "Baby-Doll Eyes"}})}, LevelUpTrigger, "Day", "", "", 700},
+		{"Charmeleon to Charizard", &Pokemon{Species: pokedex.GetSpeciesByID(5), Level: 36}, LevelUpTrigger, "Anytime", "", "", 6},
+		{"Onix to Steelix with Metal Coat", &Pokemon{Species: pokedex.GetSpeciesByID(95), Level: 25, HeldItem: MockCoat{}}, TradeTrigger, "Anytime", "", "", 208},
+		{"Magneton to Magnezone at Mt. Coronet", &Pokemon{Species: pokedex.GetSpeciesByID(82), Level: 30}, LevelUpTrigger, "Day", "", "Mt. Coronet", 462},
+		{"Sliggoo to Goodra in the rain", &Pokemon{Species: pokedex.GetSpeciesByID(705), Level: 50}, LevelUpTrigger, "Day", "Rain", "", 706},
+		{"Piloswine to Mamoswine knowing Ancient Power", &Pokemon{Species: pokedex.GetSpeciesByID(221), Level: 33, Moves: NewMoveSlots([4]Move{{Name: "Ancient Power"}})}, LevelUpTrigger, "Day", "", "", 473},
+		{"female Combee to Vespiquen", &Pokemon{Species: pokedex.GetSpeciesByID(415), Level: 21, Gender: Female}, LevelUpTrigger, "Day", "", "", 416},
+		{"male Kirlia to Gardevoir on level up", &Pokemon{Species: pokedex.GetSpeciesByID(281), Level: 30, Gender: Male}, LevelUpTrigger, "Day", "", "", 282},
+		{"Tyrogue to Hitmonlee", &Pokemon{Species: pokedex.GetSpeciesByID(236), Level: 20, Stats: Stats{Attack: 30, Defense: 25}}, LevelUpTrigger, "Day", "", "", 106},
+		{"Tyrogue to Hitmonchan", &Pokemon{Species: pokedex.GetSpeciesByID(236), Level: 20, Stats: Stats{Attack: 25, Defense: 30}}, LevelUpTrigger, "Day", "", "", 107},
+		{"Tyrogue to Hitmontop", &Pokemon{Species: pokedex.GetSpeciesByID(236), Level: 20, Stats: Stats{Attack: 30, Defense: 30}}, LevelUpTrigger, "Day", "", "", 237},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.pokemon.Evolve(pokedex, tt.currentTime, tt.currentWeather, tt.currentLocation)
+			ctx := EvolutionContext{Trigger: tt.trigger, Time: tt.currentTime, Weather: tt.currentWeather, Location: tt.currentLocation}
+			_, err := tt.pokemon.Evolve(pokedex, ctx)
 			if err != nil {
 				t.Fatalf("Evolve() error %v", err)
 			}
@@ -87,3 +171,123 @@ func TestEvolution(t *testing.T) {
 		})
 	}
 }
+
+func TestEvolutionMethods(t *testing.T) {
+	pokedex := NewPokedex(Mockdata)
+	tests := []struct {
+		name     string
+		pokemon  *Pokemon
+		ctx      EvolutionContext
+		wantID   int // 0 when no evolution should happen
+		heldItem Item
+	}{
+		{"level too low", &Pokemon{Species: pokedex.GetSpeciesByID(5), Level: 35}, EvolutionContext{}, 0, nil},
+		{"level evolution needs a level up", &Pokemon{Species: pokedex.GetSpeciesByID(5), Level: 36}, EvolutionContext{Trigger: TradeTrigger}, 0, nil},
+		{"friendship too low", &Pokemon{Species: pokedex.GetSpeciesByID(133), Friendship: 219}, EvolutionContext{Time: "Day"}, 0, nil},
+		{"stone used", &Pokemon{Species: pokedex.GetSpeciesByID(25)}, EvolutionContext{Trigger: ItemTrigger, Item: ThunderStone}, 26, nil},
+		{"wrong stone used", &Pokemon{Species: pokedex.GetSpeciesByID(25)}, EvolutionContext{Trigger: ItemTrigger, Item: FireStone}, 0, nil},
+		{"holding a stone is not using it", &Pokemon{Species: pokedex.GetSpeciesByID(25), HeldItem: ThunderStone}, EvolutionContext{}, 0, ThunderStone},
+		{"held item at night", &Pokemon{Species: pokedex.GetSpeciesByID(215), HeldItem: RazorClaw}, EvolutionContext{Time: "Night"}, 461, nil},
+		{"held item during the day", &Pokemon{Species: pokedex.GetSpeciesByID(215), HeldItem: RazorClaw}, EvolutionContext{Time: "Day"}, 0, RazorClaw},
+		{"wrong held item", &Pokemon{Species: pokedex.GetSpeciesByID(215), HeldItem: KingsRock}, EvolutionContext{Time: "Night"}, 0, KingsRock},
+		{"held item is not used from the bag", &Pokemon{Species: pokedex.GetSpeciesByID(215)}, EvolutionContext{Trigger: ItemTrigger, Item: RazorClaw, Time: "Night"}, 0, nil},
+		{"trade without the held item", &Pokemon{Species: pokedex.GetSpeciesByID(95)}, EvolutionContext{Trigger: TradeTrigger}, 0, nil},
+		{"trade consumes the held item", &Pokemon{Species: pokedex.GetSpeciesByID(95), HeldItem: MockCoat{}}, EvolutionContext{Trigger: TradeTrigger}, 208, nil},
+		{"traded for the right species", &Pokemon{Species: pokedex.GetSpeciesByID(588)}, EvolutionContext{Trigger: TradeTrigger, TradedWith: pokedex.GetSpeciesByID(616)}, 589, nil},
+		{"traded for the wrong species", &Pokemon{Species: pokedex.GetSpeciesByID(588)}, EvolutionContext{Trigger: TradeTrigger, TradedWith: pokedex.GetSpeciesByID(95)}, 0, nil},
+		{"wrong location", &Pokemon{Species: pokedex.GetSpeciesByID(82)}, EvolutionContext{Location: "Route 1"}, 0, nil},
+		{"either method of an or", &Pokemon{Species: pokedex.GetSpeciesByID(82)}, EvolutionContext{Trigger: ItemTrigger, Item: ThunderStone}, 462, nil},
+		{"no rain", &Pokemon{Species: pokedex.GetSpeciesByID(705), Level: 50}, EvolutionContext{Weather: "Clear"}, 0, nil},
+		{"rain below the level", &Pokemon{Species: pokedex.GetSpeciesByID(705), Level: 49}, EvolutionContext{Weather: "Rain"}, 0, nil},
+		{"missing move", &Pokemon{Species: pokedex.GetSpeciesByID(221), Moves: NewMoveSlots([4]Move{{Name: "Tackle"}})}, EvolutionContext{}, 0, nil},
+		{"party has the species", &Pokemon{Species: pokedex.GetSpeciesByID(458)}, EvolutionContext{Party: []*Pokemon{{Species: pokedex.GetSpeciesByID(223)}}}, 226, nil},
+		{"party lacks the species", &Pokemon{Species: pokedex.GetSpeciesByID(458)}, EvolutionContext{Party: []*Pokemon{{Species: pokedex.GetSpeciesByID(5)}}}, 0, nil},
+		{"wrong gender", &Pokemon{Species: pokedex.GetSpeciesByID(415), Level: 21, Gender: Male}, EvolutionContext{}, 0, nil},
+		{"gender and item", &Pokemon{Species: pokedex.GetSpeciesByID(281), Gender: Male}, EvolutionContext{Trigger: ItemTrigger, Item: DawnStone}, 475, nil},
+		{"item with the wrong gender", &Pokemon{Species: pokedex.GetSpeciesByID(281), Gender: Female}, EvolutionContext{Trigger: ItemTrigger, Item: DawnStone}, 0, nil},
+		{"stat comparison below the level", &Pokemon{Species: pokedex.GetSpeciesByID(236), Level: 19, Stats: Stats{Attack: 30}}, EvolutionContext{}, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := tt.pokemon.Species.ID
+			event, err := tt.pokemon.Evolve(pokedex, tt.ctx)
+
+			if tt.wantID == 0 {
+				if err == nil || tt.pokemon.Species.ID != from {
+					t.Errorf("Evolve() evolved into %v, want no evolution", tt.pokemon.Species.Name)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Evolve() error %v", err)
+				}
+				if event.Into.ID != tt.wantID || tt.pokemon.Species.ID != tt.wantID {
+					t.Errorf("Evolve() = %v, want %v", tt.pokemon.Species.ID, tt.wantID)
+				}
+			}
+
+			if !SameItem(tt.pokemon.HeldItem, tt.heldItem) {
+				t.Errorf("Evolve() left held item %v, want %v", tt.pokemon.HeldItem, tt.heldItem)
+			}
+		})
+	}
+}
+
+func TestHeldItemEvolutionConsumesItem(t *testing.T) {
+	pokedex := NewPokedex(Mockdata)
+	sneasel := &Pokemon{Species: pokedex.GetSpeciesByID(215), HeldItem: RazorClaw}
+
+	event, err := sneasel.Evolve(pokedex, EvolutionContext{Time: "Night"})
+	if err != nil {
+		t.Fatalf("Evolve() error %v", err)
+	}
+	if !SameItem(event.ConsumedItem, RazorClaw) || sneasel.HeldItem != nil {
+		t.Errorf("Expected Razor Claw to be consumed, event %+v, held %v", event, sneasel.HeldItem)
+	}
+}
+
+func TestUseEvolutionItem(t *testing.T) {
+	pokedex := NewPokedex(Mockdata)
+	engine := NewEvolutionEngine(pokedex)
+	pikachu := &Pokemon{Species: pokedex.GetSpeciesByID(25)}
+	eevee := &Pokemon{Species: pokedex.GetSpeciesByID(133)}
+	trainer := NewTrainer("Ash", [6]*Pokemon{pikachu, eevee})
+	trainer.Items = []Item{ThunderStone, OranBerry}
+
+	// Nothing happens to Eevee, so the stone stays in the bag
+	if event, err := trainer.UseEvolutionItem(eevee, ThunderStone, engine, EvolutionContext{}); err != nil || event != nil {
+		t.Fatalf("UseEvolutionItem() on Eevee = %v, %v, want nothing", event, err)
+	}
+	if len(trainer.Items) != 2 {
+		t.Fatalf("Expected Thunder Stone to stay in the bag, got %v", trainer.Items)
+	}
+
+	event, err := trainer.UseEvolutionItem(pikachu, ThunderStone, engine, EvolutionContext{})
+	if err != nil || event == nil || pikachu.Species.Name != "Raichu" {
+		t.Fatalf("UseEvolutionItem() on Pikachu = %v, %v, want Raichu", event, err)
+	}
+	if len(trainer.Items) != 1 || !SameItem(trainer.Items[0], OranBerry) {
+		t.Errorf("Expected Thunder Stone to be used up, got %v", trainer.Items)
+	}
+
+	if _, err := trainer.UseEvolutionItem(eevee, ThunderStone, engine, EvolutionContext{}); err == nil {
+		t.Errorf("Expected error using an item that isn't in the bag")
+	}
+}
+
+func TestTradeEvolutionWithPartner(t *testing.T) {
+	pokedex := NewPokedex(Mockdata)
+	engine := NewEvolutionEngine(pokedex)
+	karrablast := &Pokemon{Species: pokedex.GetSpeciesByID(588)}
+	shelmet := &Pokemon{Species: pokedex.GetSpeciesByID(616)}
+	ash := NewTrainer("Ash", [6]*Pokemon{karrablast})
+	gary := NewTrainer("Gary", [6]*Pokemon{shelmet})
+
+	events, err := engine.Trade(ash, karrablast, gary, shelmet, EvolutionContext{})
+	if err != nil {
+		t.Fatalf("Trade() error %v", err)
+	}
+	if len(events) != 1 || karrablast.Species.Name != "Escavalier" {
+		t.Errorf("Expected Karrablast to evolve when traded for Shelmet, events %+v", events)
+	}
+}
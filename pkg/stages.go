@@ -0,0 +1,86 @@
+package pokemon
+
+const maxStatStage = 6
+
+// stage points at the stage of stat, nil for HP which has none.
+func (m *StatModifiers) stage(stat Stat) *int {
+	switch stat {
+	case StatAttack:
+		return &m.Attack
+	case StatDefense:
+		return &m.Defense
+	case StatSpecialAttack:
+		return &m.SpecialAttack
+	case StatSpecialDefense:
+		return &m.SpecialDefense
+	case StatSpeed:
+		return &m.Speed
+	case StatAccuracy:
+		return &m.Accuracy
+	case StatEvasion:
+		return &m.Evasion
+	}
+	return nil
+}
+
+// Stage is the current stage of stat, 0 for HP.
+func (m *StatModifiers) Stage(stat Stat) int {
+	if stage := m.stage(stat); stage != nil {
+		return *stage
+	}
+	return 0
+}
+
+// ChangeStage raises or lowers a stat by stages, between -6 and +6, and returns how
+// far it actually moved.
+func (m *StatModifiers) ChangeStage(stat Stat, stages int) int {
+	stage := m.stage(stat)
+	if stage == nil {
+		return 0
+	}
+	old := *stage
+	*stage = clampStage(old + stages)
+	return *stage - old
+}
+
+// Reset puts every stage back to 0.
+func (m *StatModifiers) Reset() {
+	*m = StatModifiers{}
+}
+
+func clampStage(stage int) int {
+	return max(-maxStatStage, min(stage, maxStatStage))
+}
+
+// statStageMultiplier scales a stat by half of it per stage: +2 doubles it and -2
+// halves it.
+func statStageMultiplier(stage int) float64 {
+	stage = clampStage(stage)
+	if stage >= 0 {
+		return float64(2+stage) / 2
+	}
+	return 2 / float64(2-stage)
+}
+
+// accuracyStageMultiplier scales accuracy by a third per stage, the user's accuracy
+// stage less the target's evasion stage.
+func accuracyStageMultiplier(stage int) float64 {
+	stage = clampStage(stage)
+	if stage >= 0 {
+		return float64(3+stage) / 3
+	}
+	return 3 / float64(3-stage)
+}
+
+// stagedStat is one of the Pokemon's stats with its stage applied.
+func (p *Pokemon) stagedStat(stat Stat) float64 {
+	return float64(p.Stats.Get(stat)) * statStageMultiplier(p.Modifiers.Stage(stat))
+}
+
+// SwitchOut is the Pokemon leaving the field: its stat stages reset, its volatile
+// statuses end and Encore forgets its last move.
+func (p *Pokemon) SwitchOut() {
+	p.Modifiers.Reset()
+	p.StatusManager.SwitchOut(p)
+	p.lastMove = ""
+}
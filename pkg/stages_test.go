@@ -0,0 +1,163 @@
+package pokemon
+
+import "testing"
+
+func TestChangeStage(t *testing.T) {
+	tests := []struct {
+		name    string
+		stages  []int
+		want    int
+		changed int // by the last change
+	}{
+		{"raise", []int{1}, 1, 1},
+		{"raise twice", []int{2, 2}, 4, 2},
+		{"capped at +6", []int{4, 4}, 6, 2},
+		{"already maxed", []int{6, 1}, 6, 0},
+		{"lower", []int{-1}, -1, -1},
+		{"capped at -6", []int{-4, -4}, -6, -2},
+		{"back to neutral", []int{2, -2}, 0, -2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m StatModifiers
+			var changed int
+			for _, stages := range tt.stages {
+				changed = m.ChangeStage(StatEvasion, stages)
+			}
+			if m.Evasion != tt.want || changed != tt.changed {
+				t.Errorf("Evasion = %d after a change of %d, want %d after %d", m.Evasion, changed, tt.want, tt.changed)
+			}
+		})
+	}
+
+	var m StatModifiers
+	if m.ChangeStage(StatHP, 1) != 0 || m != (StatModifiers{}) {
+		t.Errorf("HP has no stage to change, got %+v", m)
+	}
+}
+
+func TestStageMultipliers(t *testing.T) {
+	tests := []struct {
+		stage    int
+		stat     float64
+		accuracy float64
+	}{
+		{-6, 2.0 / 8, 3.0 / 9},
+		{-2, 2.0 / 4, 3.0 / 5},
+		{-1, 2.0 / 3, 3.0 / 4},
+		{0, 1, 1},
+		{1, 3.0 / 2, 4.0 / 3},
+		{2, 2, 5.0 / 3},
+		{6, 4, 3},
+		{8, 4, 3},
+	}
+
+	for _, tt := range tests {
+		if got := statStageMultiplier(tt.stage); got != tt.stat {
+			t.Errorf("statStageMultiplier(%d) = %v, want %v", tt.stage, got, tt.stat)
+		}
+		if got := accuracyStageMultiplier(tt.stage); got != tt.accuracy {
+			t.Errorf("accuracyStageMultiplier(%d) = %v, want %v", tt.stage, got, tt.accuracy)
+		}
+	}
+}
+
+func TestAccuracyAndEvasionStages(t *testing.T) {
+	tests := []struct {
+		name     string
+		accuracy int
+		evasion  int
+		want     int
+	}{
+		{"neutral", 0, 0, 90},
+		{"accuracy drop", -1, 0, 67},
+		{"evasion boost", 0, 2, 54},
+		{"cancel out", 1, 1, 90},
+		{"accuracy boost", 1, 0, 120},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newDamageTestPokemon([]Type{Normal})
+			target := newDamageTestPokemon([]Type{Normal})
+			user.Modifiers.Accuracy, target.Modifiers.Evasion = tt.accuracy, tt.evasion
+			move := Move{Name: "Strike", Type: Normal, Category: Physical, Power: 80, Accuracy: 90}
+
+			if got := move.hitChance(user, target); got != tt.want {
+				t.Errorf("hitChance() = %d, want %d", got, tt.want)
+			}
+
+			// a roll just under the chance hits, one at it misses
+			setRandomizer(t, &MockRand{IntnFunc: func(n int) int { return min(tt.want-1, n-1) }})
+			if move.Execute(user, target).Missed {
+				t.Errorf("Execute() missed under a %d%% chance", tt.want)
+			}
+			setRandomizer(t, &MockRand{IntnFunc: func(n int) int { return min(tt.want, n-1) }})
+			if !move.Execute(user, target).Missed && tt.want < 100 {
+				t.Errorf("Execute() hit at a %d%% chance", tt.want)
+			}
+		})
+	}
+}
+
+func TestSpeedStageOrder(t *testing.T) {
+	setRandomizer(t, highRolls)
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	slow := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 50}, Moves: NewMoveSlots([4]Move{tackle})}
+	fast := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 90}, Moves: NewMoveSlots([4]Move{tackle})}
+	slow.Modifiers.Speed = 2
+
+	battle := NewBattle(
+		&MockBattler{Pokemon: slow, Action: BattleAction{Type: Attack}},
+		&MockBattler{Pokemon: fast, Action: BattleAction{Type: Attack}},
+	)
+	battle.MaxTurns = 1
+	battle.Run()
+
+	if order := moveOrder(battle.Log); len(order) != 2 || order[0] != "Charmander" {
+		t.Errorf("Doubled speed should outspeed 90, order %v", order)
+	}
+	if slow.Modifiers != (StatModifiers{}) {
+		t.Errorf("Stat stages should reset at the end of battle, got %+v", slow.Modifiers)
+	}
+}
+
+func TestSwitchOutResetsStages(t *testing.T) {
+	pokemon := newDamageTestPokemon([]Type{Normal})
+	pokemon.Modifiers = StatModifiers{Attack: 2, Evasion: -1}
+	pokemon.StatusManager.Secondary = []StatusEffect{&ConfusionStatus{Duration: 2}}
+	pokemon.lastMove = "Tackle"
+
+	pokemon.SwitchOut()
+
+	if pokemon.Modifiers != (StatModifiers{}) || len(pokemon.StatusManager.Secondary) != 0 || pokemon.lastMove != "" {
+		t.Errorf("SwitchOut() left stages %+v, statuses %v and last move %q", pokemon.Modifiers, pokemon.StatusManager.Secondary, pokemon.lastMove)
+	}
+}
+
+func TestEvasionAgainstMovesThatCantMiss(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tests := []struct {
+		name     string
+		move     Move
+		wantMiss bool
+	}{
+		{"accurate move", Move{Name: "Tackle", Type: Normal, Category: Physical, Power: 40, Accuracy: 100}, true},
+		{"never misses", Move{Name: "Swift", Type: Normal, Category: Special, Power: 60}, false},
+		{"targets the user", Move{Name: "Swords Dance", Type: Normal, Category: Status, Accuracy: 100, TargetsUser: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newDamageTestPokemon([]Type{Normal})
+			target := newDamageTestPokemon([]Type{Normal})
+			target.Modifiers.Evasion = maxStatStage
+
+			if got := tt.move.Execute(user, target).Missed; got != tt.wantMiss {
+				t.Errorf("Execute() missed = %v against +6 evasion, want %v", got, tt.wantMiss)
+			}
+		})
+	}
+}
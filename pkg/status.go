@@ -0,0 +1,372 @@
+package pokemon
+
+// StatusEffect is a status condition. What it does is up to the hooks it implements:
+// InflictHook, ActionOverride, ActionHook, TurnStartHook, TurnEndHook and SwitchOutHook.
+type StatusEffect interface {
+	Name() string
+}
+
+// InflictHook sets up a status as it's inflicted, such as rolling how long it lasts.
+type InflictHook interface {
+	OnInflict(p *Pokemon)
+}
+
+// ActionOverride is a status that changes the action its Pokemon chose, before the
+// turn order is worked out.
+type ActionOverride interface {
+	OverrideAction(p *Pokemon, action *BattleAction)
+}
+
+// ActionHook is a status that gets a say before its Pokemon uses a move. It can change
+// the action, or cancel it by returning false, and records why in the log.
+type ActionHook interface {
+	BeforeAction(p *Pokemon, action *BattleAction, log *BattleLog) bool
+}
+
+// TurnStartHook runs before either side acts.
+type TurnStartHook interface {
+	OnTurnStart(p *Pokemon)
+}
+
+// TurnEndHook runs once both sides have acted, returning false when the status wears off.
+type TurnEndHook interface {
+	OnTurnEnd(p *Pokemon, log *BattleLog) bool
+}
+
+// SwitchOutHook runs as the Pokemon leaves the field, returning false when switching
+// out cures the status.
+type SwitchOutHook interface {
+	OnSwitchOut(p *Pokemon) bool
+}
+
+// VolatileStatus is a secondary status, one a Pokemon can have several of next to its
+// primary status. They all end when it switches out.
+type VolatileStatus interface {
+	StatusEffect
+	Volatile()
+}
+
+// SourcedStatus is a status kept up by another Pokemon, which ends once that Pokemon
+// leaves the field.
+type SourcedStatus interface {
+	StatusSource() *Pokemon
+}
+
+// TypeImmunity is a status that can't be inflicted on Pokemon of some types.
+type TypeImmunity interface {
+	ImmuneTypes() []Type
+}
+
+const (
+	paralysisSkipChance = 25 // percent of turns a paralysed Pokemon can't move
+	thawChance          = 20 // percent of turns a frozen Pokemon thaws out
+	maxToxicCounter     = 15
+)
+
+type StatusEffectManager struct {
+	Primary   StatusEffect
+	Secondary []StatusEffect
+}
+
+func (m *StatusEffectManager) PrimaryStatus() string {
+	return m.Primary.Name()
+}
+
+// Inflict gives p a status, as its primary status or next to it for volatile ones. It
+// fails on a fainted Pokemon, one that's immune, or one that already has the status.
+func (m *StatusEffectManager) Inflict(p *Pokemon, status StatusEffect) bool {
+	if p.Health.IsFainted() || isImmune(p, status) {
+		return false
+	}
+
+	if _, volatile := status.(VolatileStatus); volatile {
+		if m.hasSecondary(status.Name()) {
+			return false
+		}
+		m.Secondary = append(m.Secondary, status)
+	} else {
+		if m.Primary != nil {
+			return false
+		}
+		m.Primary = status
+	}
+
+	if hook, ok := status.(InflictHook); ok {
+		hook.OnInflict(p)
+	}
+	return true
+}
+
+func isImmune(p *Pokemon, status StatusEffect) bool {
+	immunity, ok := status.(TypeImmunity)
+	if !ok || p.Species == nil {
+		return false
+	}
+	for _, t := range immunity.ImmuneTypes() {
+		if hasType(p.Species.Types, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *StatusEffectManager) hasSecondary(name string) bool {
+	for _, s := range m.Secondary {
+		if s.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// statuses is the primary status, if any, followed by a copy of the secondaries so
+// hooks can remove themselves while they run.
+func (m *StatusEffectManager) statuses() []StatusEffect {
+	var statuses []StatusEffect
+	if m.Primary != nil {
+		statuses = append(statuses, m.Primary)
+	}
+	return append(statuses, m.Secondary...)
+}
+
+// OverrideAction runs the ActionOverrides of p's statuses.
+func (m *StatusEffectManager) OverrideAction(p *Pokemon, action *BattleAction) {
+	for _, status := range m.statuses() {
+		if override, ok := status.(ActionOverride); ok {
+			override.OverrideAction(p, action)
+		}
+	}
+}
+
+// BeforeAction runs the ActionHooks of p's statuses, primary first, stopping at the
+// first that cancels the action.
+func (m *StatusEffectManager) BeforeAction(p *Pokemon, action *BattleAction, log *BattleLog) bool {
+	for _, status := range m.statuses() {
+		if hook, ok := status.(ActionHook); ok && !hook.BeforeAction(p, action, log) {
+			return false
+		}
+	}
+	return true
+}
+
+// TurnStart runs the TurnStartHooks of p's statuses.
+func (m *StatusEffectManager) TurnStart(p *Pokemon) {
+	for _, status := range m.statuses() {
+		if hook, ok := status.(TurnStartHook); ok {
+			hook.OnTurnStart(p)
+		}
+	}
+}
+
+// TurnEnd runs the TurnEndHooks of p's statuses, removing those that wear off. A hook
+// that faints the Pokemon replaces its primary status, which is then left alone.
+func (m *StatusEffectManager) TurnEnd(p *Pokemon, log *BattleLog) {
+	keep := func(status StatusEffect) bool {
+		hook, ok := status.(TurnEndHook)
+		return !ok || hook.OnTurnEnd(p, log)
+	}
+
+	if primary := m.Primary; primary != nil && !keep(primary) && m.Primary == primary {
+		m.Primary = nil
+	}
+	for _, status := range append([]StatusEffect(nil), m.Secondary...) {
+		if !keep(status) {
+			m.RemoveSecondary(status)
+		}
+	}
+}
+
+// SwitchOut clears p's volatile statuses and runs the SwitchOutHook of its primary
+// status, which may cure it.
+func (m *StatusEffectManager) SwitchOut(p *Pokemon) {
+	m.Secondary = nil
+	if hook, ok := m.Primary.(SwitchOutHook); ok && !hook.OnSwitchOut(p) {
+		m.Primary = nil
+	}
+}
+
+// SourceLeft ends the statuses that source kept up, now that it has left the field.
+func (m *StatusEffectManager) SourceLeft(source *Pokemon) {
+	for _, status := range append([]StatusEffect(nil), m.Secondary...) {
+		if sourced, ok := status.(SourcedStatus); ok && sourced.StatusSource() == source {
+			m.RemoveSecondary(status)
+		}
+	}
+}
+
+// RemoveSecondary takes status off the Pokemon.
+func (m *StatusEffectManager) RemoveSecondary(status StatusEffect) {
+	for i, s := range m.Secondary {
+		if s == status {
+			m.Secondary = append(m.Secondary[:i], m.Secondary[i+1:]...)
+			return
+		}
+	}
+}
+
+// residualDamage hurts p by a fraction of its max HP, at least 1.
+func residualDamage(p *Pokemon, numerator, denominator int) {
+	p.TakeDamage(atLeastOne(p.Health.Max * numerator / denominator))
+}
+
+// FaintedStatus remains until removed by a special item or being healed by a healer.
+type FaintedStatus struct{}
+
+func (f *FaintedStatus) Name() string {
+	return "Fainted"
+}
+
+// SleepStatus stops the Pokemon from moving for Duration of its turns, rolled between
+// 1 and 3 when it's inflicted without one.
+type SleepStatus struct {
+	Duration int
+}
+
+func (s *SleepStatus) OnInflict(p *Pokemon) {
+	if s.Duration <= 0 {
+		s.Duration = rng.Intn(3) + 1
+	}
+}
+
+// BeforeAction counts sleep down, it only passes on the turns the Pokemon tries to move.
+func (s *SleepStatus) BeforeAction(p *Pokemon, _ *BattleAction, log *BattleLog) bool {
+	if s.Duration <= 0 {
+		p.StatusManager.Primary = nil
+		log.Add("%s woke up!", p.Name())
+		return true
+	}
+	s.Duration--
+	log.Add("%s is fast asleep.", p.Name())
+	return false
+}
+
+func (s *SleepStatus) Name() string {
+	return "Sleep"
+}
+
+// PoisonStatus deals 1/8 of max HP at the end of every turn.
+type PoisonStatus struct{}
+
+func (s *PoisonStatus) OnTurnEnd(p *Pokemon, log *BattleLog) bool {
+	residualDamage(p, 1, 8)
+	log.Add("%s is hurt by poison!", p.Name())
+	return true
+}
+
+func (s *PoisonStatus) ImmuneTypes() []Type {
+	return []Type{Poison, Steel}
+}
+
+func (s *PoisonStatus) Name() string {
+	return "Poison"
+}
+
+// BurnStatus deals 1/16 of max HP at the end of every turn and halves physical attack.
+type BurnStatus struct{}
+
+func (b *BurnStatus) OnTurnEnd(p *Pokemon, log *BattleLog) bool {
+	residualDamage(p, 1, 16)
+	log.Add("%s is hurt by its burn!", p.Name())
+	return true
+}
+
+func (b *BurnStatus) ImmuneTypes() []Type {
+	return []Type{Fire}
+}
+
+func (b *BurnStatus) Name() string {
+	return "Burn"
+}
+
+// ParalysisStatus halves speed and sometimes stops the Pokemon from moving.
+type ParalysisStatus struct{}
+
+func (s *ParalysisStatus) BeforeAction(p *Pokemon, _ *BattleAction, log *BattleLog) bool {
+	if rng.Intn(100) < paralysisSkipChance {
+		log.Add("%s is paralyzed! It can't move!", p.Name())
+		return false
+	}
+	return true
+}
+
+func (s *ParalysisStatus) ImmuneTypes() []Type {
+	return []Type{Electric}
+}
+
+func (s *ParalysisStatus) Name() string {
+	return "Paralysis"
+}
+
+// FreezeStatus stops the Pokemon from moving until it thaws, either by chance at the
+// start of its turn or by being hit with a Fire move.
+type FreezeStatus struct{}
+
+func (f *FreezeStatus) BeforeAction(p *Pokemon, _ *BattleAction, log *BattleLog) bool {
+	if rng.Intn(100) < thawChance {
+		p.StatusManager.Primary = nil
+		log.Add("%s thawed out!", p.Name())
+		return true
+	}
+	log.Add("%s is frozen solid!", p.Name())
+	return false
+}
+
+func (f *FreezeStatus) ImmuneTypes() []Type {
+	return []Type{Ice}
+}
+
+func (f *FreezeStatus) Name() string {
+	return "Freeze"
+}
+
+// ToxicStatus is bad poison, dealing 1/16 of max HP more every turn it lasts. The count
+// starts over when the Pokemon switches out.
+type ToxicStatus struct {
+	Counter int
+}
+
+func (t *ToxicStatus) OnInflict(p *Pokemon) {
+	t.Counter = 0
+}
+
+func (t *ToxicStatus) OnTurnEnd(p *Pokemon, log *BattleLog) bool {
+	t.Counter = min(t.Counter+1, maxToxicCounter)
+	residualDamage(p, t.Counter, 16)
+	log.Add("%s is hurt by poison!", p.Name())
+	return true
+}
+
+func (t *ToxicStatus) OnSwitchOut(p *Pokemon) bool {
+	t.Counter = 0
+	return true
+}
+
+func (t *ToxicStatus) ImmuneTypes() []Type {
+	return []Type{Poison, Steel}
+}
+
+func (t *ToxicStatus) Name() string {
+	return "Toxic"
+}
+
+func (p *Pokemon) isBurned() bool {
+	_, burned := p.StatusManager.Primary.(*BurnStatus)
+	return burned
+}
+
+// effectiveSpeed is the speed used to order turns, with its stage applied and halved
+// by paralysis.
+func (p *Pokemon) effectiveSpeed() int {
+	speed := int(p.stagedStat(StatSpeed))
+	if _, paralysed := p.StatusManager.Primary.(*ParalysisStatus); paralysed {
+		return speed / 2
+	}
+	return speed
+}
+
+// thawIfFire unfreezes a target hit by a damaging Fire move.
+func thawIfFire(target *Pokemon, move *Move, result DamageResult) {
+	if _, frozen := target.StatusManager.Primary.(*FreezeStatus); frozen && move.Type == Fire && result.Damage > 0 {
+		target.StatusManager.Primary = nil
+	}
+}
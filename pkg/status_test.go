@@ -0,0 +1,311 @@
+package pokemon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResidualStatusDamage(t *testing.T) {
+	tests := []struct {
+		name   string
+		status StatusEffect
+		want   []int // health after each turn, from 160
+	}{
+		{"burn", &BurnStatus{}, []int{150, 140, 130}},
+		{"toxic", &ToxicStatus{}, []int{150, 130, 100}},
+		{"paralysis", &ParalysisStatus{}, []int{160, 160, 160}},
+		{"freeze", &FreezeStatus{}, []int{160, 160, 160}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pokemon := &Pokemon{Health: Health{Current: 160, Max: 160}}
+			pokemon.StatusManager.Primary = tt.status
+			for turn, want := range tt.want {
+				pokemon.StatusManager.TurnEnd(pokemon, nil)
+				if pokemon.Health.Current != want {
+					t.Errorf("turn %d health = %d, want %d", turn+1, pokemon.Health.Current, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPoisonDealsDamage(t *testing.T) {
+	pokemon := &Pokemon{Health: Health{Current: 160, Max: 160}}
+
+	if !pokemon.StatusManager.Inflict(pokemon, &PoisonStatus{}) || pokemon.Health.Current != 160 {
+		t.Fatalf("Expected poisoning to inflict the status without damage, got %v at %d", pokemon.StatusManager.Primary, pokemon.Health.Current)
+	}
+
+	pokemon.StatusManager.TurnEnd(pokemon, nil)
+	if pokemon.Health.Current != 140 {
+		t.Errorf("Poison should deal 1/8 of max HP, health = %d", pokemon.Health.Current)
+	}
+}
+
+func TestSecondaryEffect(t *testing.T) {
+	tests := []struct {
+		name    string
+		effect  SecondaryEffect
+		types   []Type
+		primary StatusEffect
+		roll    int
+		want    string // the target's primary status afterwards, "" for none
+	}{
+		{"inflicted", SecondaryEffect{Status: "poison", Chance: 30}, []Type{Normal}, nil, 29, "Poison"},
+		{"chance missed", SecondaryEffect{Status: "poison", Chance: 30}, []Type{Normal}, nil, 30, ""},
+		{"always", SecondaryEffect{Status: "burn"}, []Type{Normal}, nil, 99, "Burn"},
+		{"poison types can't be poisoned", SecondaryEffect{Status: "poison"}, []Type{Grass, Poison}, nil, 0, ""},
+		{"steel types can't be badly poisoned", SecondaryEffect{Status: "toxic"}, []Type{Steel}, nil, 0, ""},
+		{"fire types can't be burned", SecondaryEffect{Status: "burn"}, []Type{Fire}, nil, 0, ""},
+		{"ice types can't be frozen", SecondaryEffect{Status: "freeze"}, []Type{Ice}, nil, 0, ""},
+		{"electric types can't be paralysed", SecondaryEffect{Status: "paralysis"}, []Type{Electric}, nil, 0, ""},
+		{"one primary status at a time", SecondaryEffect{Status: "poison"}, []Type{Normal}, &BurnStatus{}, 0, "Burn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRandomizer(t, &MockRand{IntnFunc: func(n int) int { return min(tt.roll, n-1) }})
+			target := newDamageTestPokemon(tt.types)
+			target.StatusManager.Primary = tt.primary
+
+			tt.effect.Apply(nil, target, DamageResult{Effectiveness: 1})
+
+			got := ""
+			if target.StatusManager.Primary != nil {
+				got = target.StatusManager.PrimaryStatus()
+			}
+			if got != tt.want {
+				t.Errorf("target status = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInflictHooks(t *testing.T) {
+	setRandomizer(t, &MockRand{IntnFunc: func(n int) int { return 1 }})
+	pokemon := newDamageTestPokemon([]Type{Normal})
+
+	sleep := &SleepStatus{}
+	if !pokemon.StatusManager.Inflict(pokemon, sleep) || sleep.Duration != 2 {
+		t.Errorf("Sleep should roll its duration when inflicted, got %d", sleep.Duration)
+	}
+
+	confusion := &ConfusionStatus{Duration: 2}
+	if !pokemon.StatusManager.Inflict(pokemon, confusion) || len(pokemon.StatusManager.Secondary) != 1 {
+		t.Errorf("A volatile status should go next to the primary status, got %v", pokemon.StatusManager.Secondary)
+	}
+	if pokemon.StatusManager.Inflict(pokemon, &ConfusionStatus{Duration: 2}) {
+		t.Errorf("A Pokemon can't be confused twice")
+	}
+
+	pokemon.Health.Current = 0
+	if pokemon.StatusManager.Inflict(pokemon, &FlinchStatus{}) {
+		t.Errorf("A fainted Pokemon can't take a status")
+	}
+}
+
+func TestSwitchOut(t *testing.T) {
+	pokemon := newDamageTestPokemon([]Type{Normal})
+	toxic := &ToxicStatus{Counter: 3}
+	pokemon.StatusManager.Primary = toxic
+	pokemon.StatusManager.Secondary = []StatusEffect{&ConfusionStatus{Duration: 2}, &FlinchStatus{}}
+
+	pokemon.StatusManager.SwitchOut(pokemon)
+
+	if pokemon.StatusManager.Primary != toxic || toxic.Counter != 0 {
+		t.Errorf("Switching out should keep toxic with its counter reset, got %v", pokemon.StatusManager.Primary)
+	}
+	if len(pokemon.StatusManager.Secondary) != 0 {
+		t.Errorf("Switching out should cure confusion and flinching, got %v", pokemon.StatusManager.Secondary)
+	}
+}
+
+// turnStartRecorder counts the turns it has seen start.
+type turnStartRecorder struct {
+	turns int
+}
+
+func (r *turnStartRecorder) OnTurnStart(p *Pokemon) {
+	r.turns++
+}
+
+func (r *turnStartRecorder) Volatile() {}
+
+func (r *turnStartRecorder) Name() string {
+	return "Recorder"
+}
+
+func TestBattleTurnStart(t *testing.T) {
+	pokemon := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 90}}
+	other := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 50}}
+	recorder := &turnStartRecorder{}
+	pokemon.StatusManager.Secondary = []StatusEffect{recorder}
+
+	battle := NewBattle(&MockBattler{Pokemon: pokemon, Action: BattleAction{Type: Flee}}, &MockBattler{Pokemon: other, Action: BattleAction{Type: Flee}})
+	battle.MaxTurns = 1
+	battle.Run()
+
+	if recorder.turns != 1 {
+		t.Errorf("OnTurnStart ran %d times, want once for the single turn", recorder.turns)
+	}
+}
+
+func TestResidualDamageFaints(t *testing.T) {
+	pokemon := &Pokemon{Health: Health{Current: 1, Max: 10}}
+	pokemon.StatusManager.Primary = &BurnStatus{}
+
+	pokemon.StatusManager.TurnEnd(pokemon, nil)
+	if !pokemon.Health.IsFainted() || pokemon.StatusManager.PrimaryStatus() != "Fainted" {
+		t.Errorf("Expected the burn to faint the Pokemon, status %v", pokemon.StatusManager.Primary)
+	}
+}
+
+func TestBurnHalvesPhysicalAttack(t *testing.T) {
+	setRandomizer(t, highRolls)
+	tackle := Move{Name: "Strike", Type: Normal, Category: Physical, Power: 80, Accuracy: 100}
+	beam := Move{Name: "Beam", Type: Normal, Category: Special, Power: 80, Accuracy: 100}
+
+	user := newDamageTestPokemon([]Type{Fire})
+	target := newDamageTestPokemon([]Type{Normal})
+	user.StatusManager.Primary = &BurnStatus{}
+
+	// halving 100 Attack matches the user's 50 Special Attack
+	if got := CalculateDamage(user, target, &tackle); got.Damage != 19 {
+		t.Errorf("Burned physical damage = %d, want 19 instead of 37", got.Damage)
+	}
+	if got := CalculateDamage(user, target, &beam); got.Damage != 19 {
+		t.Errorf("Burned special damage = %d, want the unburned 19", got.Damage)
+	}
+}
+
+func TestBeforeAction(t *testing.T) {
+	tests := []struct {
+		name      string
+		primary   StatusEffect
+		secondary StatusEffect
+		roll      int
+		want      bool
+		log       []string
+		remaining int // statuses left afterwards
+	}{
+		{"paralysed", &ParalysisStatus{}, nil, 24, false, []string{"Pikachu is paralyzed! It can't move!"}, 1},
+		{"paralysis lets it move", &ParalysisStatus{}, nil, 25, true, nil, 1},
+		{"frozen", &FreezeStatus{}, nil, 20, false, []string{"Pikachu is frozen solid!"}, 1},
+		{"thaws out", &FreezeStatus{}, nil, 19, true, []string{"Pikachu thawed out!"}, 0},
+		{"asleep", &SleepStatus{Duration: 1}, nil, 0, false, []string{"Pikachu is fast asleep."}, 1},
+		{"wakes up", &SleepStatus{}, nil, 0, true, []string{"Pikachu woke up!"}, 0},
+		{"burn never stops it", &BurnStatus{}, nil, 0, true, nil, 1},
+		{"flinch", nil, &FlinchStatus{}, 0, false, []string{"Pikachu flinched and couldn't move!"}, 1},
+		{"confused", nil, &ConfusionStatus{Duration: 2}, 33, true, []string{"Pikachu is confused!"}, 1},
+		{"confusion self hit", nil, &ConfusionStatus{Duration: 2}, 32, false, []string{"Pikachu is confused!", "It hurt itself in its confusion!"}, 1},
+		{"confusion ends", nil, &ConfusionStatus{}, 0, true, []string{"Pikachu snapped out of its confusion!"}, 0},
+		{"sleep comes first", &SleepStatus{Duration: 1}, &ConfusionStatus{Duration: 2}, 0, false, []string{"Pikachu is fast asleep."}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRandomizer(t, &MockRand{IntnFunc: func(n int) int { return min(tt.roll, n-1) }})
+			pokemon := &Pokemon{Species: &Species{Name: "Pikachu"}, Level: 50, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 50, Defense: 50}}
+			pokemon.StatusManager.Primary = tt.primary
+			if tt.secondary != nil {
+				pokemon.StatusManager.Secondary = []StatusEffect{tt.secondary}
+			}
+
+			var log BattleLog
+			action := BattleAction{Type: Attack}
+			if got := pokemon.StatusManager.BeforeAction(pokemon, &action, &log); got != tt.want {
+				t.Errorf("BeforeAction() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(log.Messages, tt.log) {
+				t.Errorf("log = %q, want %q", log.Messages, tt.log)
+			}
+
+			remaining := len(pokemon.StatusManager.Secondary)
+			if pokemon.StatusManager.Primary != nil {
+				remaining++
+			}
+			if remaining != tt.remaining {
+				t.Errorf("%d statuses left, want %d", remaining, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestConfusionSelfHit(t *testing.T) {
+	setRandomizer(t, &MockRand{IntnFunc: func(n int) int { return 0 }})
+	pokemon := &Pokemon{Level: 50, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 100, Defense: 100}}
+
+	// ((2*50/5+2)*40*100/100)/50 + 2 = 19, at the lowest roll 16
+	if got := confusionDamage(pokemon); got != 16 {
+		t.Errorf("confusionDamage() = %d, want 16", got)
+	}
+}
+
+func TestFireMoveThaws(t *testing.T) {
+	setRandomizer(t, highRolls)
+	ember := Move{Name: "Ember", Type: Fire, Category: Special, Power: 40, Accuracy: 100}
+	tackle := Move{Name: "Tackle", Type: Normal, Category: Physical, Power: 40, Accuracy: 100}
+
+	target := newDamageTestPokemon([]Type{Normal})
+	target.StatusManager.Primary = &FreezeStatus{}
+
+	tackle.Execute(newDamageTestPokemon([]Type{Normal}), target)
+	if target.StatusManager.Primary == nil {
+		t.Fatalf("A Normal move shouldn't thaw the target")
+	}
+	ember.Execute(newDamageTestPokemon([]Type{Fire}), target)
+	if target.StatusManager.Primary != nil {
+		t.Errorf("A Fire move should thaw the target, status %v", target.StatusManager.Primary)
+	}
+}
+
+func TestBattleParalysis(t *testing.T) {
+	// every paralysis check fails, every other roll is high
+	setRandomizer(t, &MockRand{IntnFunc: func(n int) int {
+		if n == 100 {
+			return 0
+		}
+		return n - 1
+	}})
+
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	paralysed := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 90}, Moves: NewMoveSlots([4]Move{tackle})}
+	other := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 50}, Moves: NewMoveSlots([4]Move{tackle})}
+	paralysed.StatusManager.Primary = &ParalysisStatus{}
+
+	battle := NewBattle(
+		&MockBattler{Pokemon: paralysed, Action: BattleAction{Type: Attack}},
+		&MockBattler{Pokemon: other, Action: BattleAction{Type: Attack}},
+	)
+	battle.MaxTurns = 1
+	battle.Run()
+
+	if order := moveOrder(battle.Log); len(order) != 1 || order[0] != "Bulbasaur" {
+		t.Errorf("Expected only Bulbasaur to act, outspeeding the paralysed Charmander, got %v", order)
+	}
+	if other.Health.Current != 100 || paralysed.Moves[0].PP != 35 {
+		t.Errorf("A fully paralysed Pokemon shouldn't attack or spend PP, health %d, PP %d", other.Health.Current, paralysed.Moves[0].PP)
+	}
+}
+
+func TestBattleBurnDamage(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	splash := Move{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}
+	burned := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 160, Max: 160}, Stats: Stats{Speed: 90}, Moves: NewMoveSlots([4]Move{splash})}
+	other := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 50}, Moves: NewMoveSlots([4]Move{splash})}
+	burned.StatusManager.Primary = &BurnStatus{}
+
+	battle := NewBattle(&MockBattler{Pokemon: burned, Action: BattleAction{Type: Attack}}, &MockBattler{Pokemon: other, Action: BattleAction{Type: Attack}})
+	battle.MaxTurns = 1
+	battle.Run()
+
+	if burned.Health.Current != 150 {
+		t.Errorf("Expected the burn to deal 10 at the end of the turn, health = %d", burned.Health.Current)
+	}
+	if want := []string{"Charmander used Splash!", "Bulbasaur used Splash!", "Charmander is hurt by its burn!"}; !reflect.DeepEqual(battle.Log.Messages, want) {
+		t.Errorf("log = %q, want %q", battle.Log.Messages, want)
+	}
+}
@@ -1,16 +1,21 @@
 package pokemon
 
+import "fmt"
+
+// TeamSize is how many Pokemon a trainer can carry.
+const TeamSize = 6
+
 type Trainer struct {
 	ID           string
 	Name         string
-	Team         [6]*Pokemon
+	Team         [TeamSize]*Pokemon
 	Items        []Item
 	Location     string
 	Pokedex      PokedexRepository
 	Achievements []Achievement
 }
 
-func NewTrainer(name string, team [6]*Pokemon) *Trainer {
+func NewTrainer(name string, team [TeamSize]*Pokemon) *Trainer {
 	return &Trainer{
 		Name: name,
 		Team: team,
@@ -69,6 +74,110 @@ func (b *Badge) Earned() bool {
 	return b.earned
 }
 
-// func(t *Trainer) Trade(myPokemon *Pokemon, otherTrainer *Trainer, theirPokemon *Pokemon)
+// RemoveItem takes one of item out of the bag, returning false if there is none.
+func (t *Trainer) RemoveItem(item Item) bool {
+	for i, it := range t.Items {
+		if SameItem(it, item) {
+			t.Items = append(t.Items[:i], t.Items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// UseEvolutionItem uses item from the bag on pokemon, consuming it only if pokemon evolves.
+func (t *Trainer) UseEvolutionItem(pokemon *Pokemon, item Item, engine *EvolutionEngine, ctx EvolutionContext) (*EvolutionEvent, error) {
+	if t.teamIndex(pokemon) < 0 {
+		return nil, fmt.Errorf("%s is not on %s's team", pokemon.Species.Name, t.Name)
+	}
+	if !t.hasItem(item) {
+		return nil, fmt.Errorf("%s has no %s", t.Name, item.Name())
+	}
+
+	ctx.Party = t.Team[:]
+	event, err := engine.AfterItem(pokemon, item, ctx)
+	if err != nil || event == nil {
+		return nil, err
+	}
+	if !event.Cancelled && event.ConsumedItem != nil {
+		t.RemoveItem(event.ConsumedItem)
+	}
+	return event, nil
+}
+
+func (t *Trainer) hasItem(item Item) bool {
+	for _, it := range t.Items {
+		if SameItem(it, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Trade swaps one of t's Pokemon for one of otherTrainer's.
+func (t *Trainer) Trade(myPokemon *Pokemon, otherTrainer *Trainer, theirPokemon *Pokemon) error {
+	mine, theirs := t.teamIndex(myPokemon), otherTrainer.teamIndex(theirPokemon)
+	if mine < 0 || theirs < 0 {
+		return fmt.Errorf("both Pokemon must be on their trainer's team to trade")
+	}
+
+	t.Team[mine], otherTrainer.Team[theirs] = theirPokemon, myPokemon
+	return nil
+}
+
+func (t *Trainer) teamIndex(pokemon *Pokemon) int {
+	for i, p := range t.Team {
+		if p != nil && p == pokemon {
+			return i
+		}
+	}
+	return -1
+}
 
 // Quests and or Challenges ?
+
+// HasUsablePokemon reports whether any Pokemon on the team can still fight.
+func (t *Trainer) HasUsablePokemon() bool {
+	for _, pokemon := range t.Team {
+		if pokemon != nil && !pokemon.Health.IsFainted() {
+			return true
+		}
+	}
+	return false
+}
+
+// PokemonAt is the Pokemon in a team slot, nil for an empty or invalid slot.
+func (t *Trainer) PokemonAt(slot int) *Pokemon {
+	if slot < 0 || slot >= len(t.Team) {
+		return nil
+	}
+	return t.Team[slot]
+}
+
+// ChooseReplacement sends out the first healthy Pokemon after the active one, or -1
+// if there is none.
+func (t *Trainer) ChooseReplacement() int {
+	for slot := 1; slot < len(t.Team); slot++ {
+		if pokemon := t.Team[slot]; pokemon != nil && !pokemon.Health.IsFainted() {
+			return slot
+		}
+	}
+	return -1
+}
+
+// UseItemOn uses item from the bag on the Pokemon in slot, or on its move in moveSlot
+// for items like an Ether, consuming it only if it worked.
+func (t *Trainer) UseItemOn(slot, moveSlot int, item Item) error {
+	pokemon := t.PokemonAt(slot)
+	if pokemon == nil {
+		return fmt.Errorf("%s has no Pokemon in slot %d", t.Name, slot)
+	}
+	if !t.hasItem(item) {
+		return fmt.Errorf("%s has no %s", t.Name, item.Name())
+	}
+	if err := pokemon.ApplyItem(item, moveSlot); err != nil {
+		return err
+	}
+	t.RemoveItem(item)
+	return nil
+}
@@ -0,0 +1,121 @@
+package pokemon
+
+import "fmt"
+
+// TypeChart holds how effective each attacking type is against each defending type.
+// Matchups that aren't listed are neutral.
+type TypeChart struct {
+	matchups map[Type]map[Type]float64
+}
+
+func NewTypeChart(matchups map[Type]map[Type]float64) *TypeChart {
+	c := &TypeChart{matchups: make(map[Type]map[Type]float64)}
+	for attacking, row := range matchups {
+		for defending, multiplier := range row {
+			c.SetMatchup(attacking, defending, multiplier)
+		}
+	}
+	return c
+}
+
+// LoadTypeChartFromJSON reads a chart keyed by type names, e.g. {"Fire": {"Grass": 2, "Water": 0.5}}.
+func LoadTypeChartFromJSON(handler FileIOHandler, filename string) (*TypeChart, error) {
+	var matchups map[Type]map[Type]float64
+	if err := LoadFromJSON(handler, filename, &matchups); err != nil {
+		return nil, fmt.Errorf("type chart: %w", err)
+	}
+	return NewTypeChart(matchups), nil
+}
+
+// TypeChartForGeneration builds the chart used by the given generation, leaving out
+// types that didn't exist yet.
+func TypeChartForGeneration(gen Generation) *TypeChart {
+	layers := []map[Type]map[Type]float64{gen2TypeMatchups}
+	switch {
+	case gen <= 1:
+		layers = append(layers, gen1TypeMatchups)
+	case gen >= 6:
+		layers = append(layers, gen6TypeMatchups)
+	}
+
+	c := NewTypeChart(nil)
+	for _, layer := range layers {
+		for attacking, row := range layer {
+			for defending, multiplier := range row {
+				if attacking.AvailableIn(gen) && defending.AvailableIn(gen) {
+					c.SetMatchup(attacking, defending, multiplier)
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *TypeChart) SetMatchup(attacking, defending Type, multiplier float64) {
+	if c.matchups[attacking] == nil {
+		c.matchups[attacking] = make(map[Type]float64)
+	}
+	c.matchups[attacking][defending] = multiplier
+}
+
+// Matchup returns the multiplier for attacking against a single defending type.
+func (c *TypeChart) Matchup(attacking, defending Type) float64 {
+	if multiplier, exists := c.matchups[attacking][defending]; exists {
+		return multiplier
+	}
+	return 1.0
+}
+
+// Effectiveness returns the combined multiplier against all of the defender's types,
+// so dual types can give 0, 0.25, 0.5, 1, 2 or 4.
+func (c *TypeChart) Effectiveness(attacking Type, defending []Type) float64 {
+	effectiveness := 1.0
+	for _, t := range defending {
+		effectiveness *= c.Matchup(attacking, t)
+	}
+	return effectiveness
+}
+
+// DefaultTypeChart is used for damage calculation.
+var DefaultTypeChart = TypeChartForGeneration(LatestGeneration)
+
+// Generation 2 to 5 chart, only listing matchups that aren't neutral.
+var gen2TypeMatchups = map[Type]map[Type]float64{
+	Normal:   {Rock: 0.5, Ghost: 0, Steel: 0.5},
+	Fire:     {Fire: 0.5, Water: 0.5, Grass: 2, Ice: 2, Bug: 2, Rock: 0.5, Dragon: 0.5, Steel: 2},
+	Water:    {Fire: 2, Water: 0.5, Grass: 0.5, Ground: 2, Rock: 2, Dragon: 0.5},
+	Electric: {Water: 2, Electric: 0.5, Grass: 0.5, Ground: 0, Flying: 2, Dragon: 0.5},
+	Grass:    {Fire: 0.5, Water: 2, Grass: 0.5, Poison: 0.5, Ground: 2, Flying: 0.5, Bug: 0.5, Rock: 2, Dragon: 0.5, Steel: 0.5},
+	Ice:      {Fire: 0.5, Water: 0.5, Grass: 2, Ice: 0.5, Ground: 2, Flying: 2, Dragon: 2, Steel: 0.5},
+	Fighting: {Normal: 2, Ice: 2, Poison: 0.5, Flying: 0.5, Psychic: 0.5, Bug: 0.5, Rock: 2, Ghost: 0, Dark: 2, Steel: 2},
+	Poison:   {Grass: 2, Poison: 0.5, Ground: 0.5, Rock: 0.5, Ghost: 0.5, Steel: 0},
+	Ground:   {Fire: 2, Electric: 2, Grass: 0.5, Poison: 2, Flying: 0, Bug: 0.5, Rock: 2, Steel: 2},
+	Flying:   {Electric: 0.5, Grass: 2, Fighting: 2, Bug: 2, Rock: 0.5, Steel: 0.5},
+	Psychic:  {Fighting: 2, Poison: 2, Psychic: 0.5, Dark: 0, Steel: 0.5},
+	Bug:      {Fire: 0.5, Grass: 2, Fighting: 0.5, Poison: 0.5, Flying: 0.5, Psychic: 2, Ghost: 0.5, Dark: 2, Steel: 0.5},
+	Rock:     {Fire: 2, Ice: 2, Fighting: 0.5, Ground: 0.5, Flying: 2, Bug: 2, Steel: 0.5},
+	Ghost:    {Normal: 0, Psychic: 2, Ghost: 2, Dark: 0.5, Steel: 0.5},
+	Dragon:   {Dragon: 2, Steel: 0.5},
+	Dark:     {Fighting: 0.5, Psychic: 2, Ghost: 2, Dark: 0.5, Steel: 0.5},
+	Steel:    {Fire: 0.5, Water: 0.5, Electric: 0.5, Ice: 2, Rock: 2, Steel: 0.5},
+}
+
+// Generation 1 differences from the generation 2 chart.
+var gen1TypeMatchups = map[Type]map[Type]float64{
+	Bug:    {Poison: 2},
+	Ghost:  {Psychic: 0},
+	Ice:    {Fire: 1},
+	Poison: {Bug: 2},
+}
+
+// Generation 6 onwards adds Fairy and stops Steel resisting Ghost and Dark.
+var gen6TypeMatchups = map[Type]map[Type]float64{
+	Fighting: {Fairy: 0.5},
+	Poison:   {Fairy: 2},
+	Bug:      {Fairy: 0.5},
+	Ghost:    {Steel: 1},
+	Dragon:   {Fairy: 0},
+	Dark:     {Steel: 1, Fairy: 0.5},
+	Steel:    {Fairy: 2},
+	Fairy:    {Fire: 0.5, Fighting: 2, Poison: 0.5, Dragon: 2, Dark: 2, Steel: 0.5},
+}
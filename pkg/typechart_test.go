@@ -0,0 +1,77 @@
+package pokemon
+
+import "testing"
+
+func TestTypeChartEffectiveness(t *testing.T) {
+	tests := []struct {
+		name      string
+		attacking Type
+		defending []Type
+		want      float64
+	}{
+		{"neutral", Normal, []Type{Water}, 1},
+		{"super effective", Water, []Type{Fire}, 2},
+		{"not very effective", Fire, []Type{Water}, 0.5},
+		{"immune", Electric, []Type{Ground}, 0},
+		{"double super effective", Ice, []Type{Dragon, Flying}, 4},
+		{"double resisted", Grass, []Type{Fire, Flying}, 0.25},
+		{"dual type cancels out", Fire, []Type{Grass, Water}, 1},
+		{"immunity wins over weakness", Ground, []Type{Flying, Electric}, 0},
+		{"no types", Fire, nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultTypeChart.Effectiveness(tt.attacking, tt.defending); got != tt.want {
+				t.Errorf("Effectiveness(%v, %v) = %v, want %v", tt.attacking, tt.defending, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTypeChartFromJSON(t *testing.T) {
+	mockHandler := &MockFileIOHandler{FileData: map[string][]byte{
+		"chart.json": []byte(`{"Fire": {"Grass": 2, "water": 0.5}, "Ghost": {"Normal": 0}}`),
+	}}
+
+	chart, err := LoadTypeChartFromJSON(mockHandler, "chart.json")
+	if err != nil {
+		t.Fatalf("LoadTypeChartFromJSON() error = %v", err)
+	}
+
+	if got := chart.Effectiveness(Fire, []Type{Grass, Water}); got != 1 {
+		t.Errorf("Effectiveness(Fire, Grass/Water) = %v, want 1", got)
+	}
+	if got := chart.Matchup(Ghost, Normal); got != 0 {
+		t.Errorf("Matchup(Ghost, Normal) = %v, want 0", got)
+	}
+	if got := chart.Matchup(Water, Fire); got != 1 {
+		t.Errorf("Matchup(Water, Fire) = %v, want 1 for unlisted matchup", got)
+	}
+}
+
+func TestLoadTypeChartFromJSON_UnknownType(t *testing.T) {
+	mockHandler := &MockFileIOHandler{FileData: map[string][]byte{
+		"chart.json": []byte(`{"Fire": {"Sound": 2}}`),
+	}}
+
+	if _, err := LoadTypeChartFromJSON(mockHandler, "chart.json"); err == nil {
+		t.Errorf("Expected error for unknown type, got nil")
+	}
+}
+
+func TestDamageUsesTypeChart(t *testing.T) {
+	setRandomizer(t, highRolls)
+	user := newDamageTestPokemon([]Type{Normal})
+	move := Move{Name: "Splash Jet", Type: Water, Category: Physical, Power: 80, Accuracy: 100}
+
+	weak := CalculateDamage(user, newDamageTestPokemon([]Type{Fire, Rock}), &move)
+	if weak.Effectiveness != 4 || weak.Damage != 148 {
+		t.Errorf("CalculateDamage() against Fire/Rock = %+v, want 4x for 148", weak)
+	}
+
+	immune := CalculateDamage(user, newDamageTestPokemon([]Type{Ground}), &Move{Type: Electric, Category: Special, Power: 90})
+	if immune.Effectiveness != 0 || immune.Damage != 0 {
+		t.Errorf("CalculateDamage() against Ground = %+v, want no damage", immune)
+	}
+}
@@ -2,6 +2,7 @@ package pokemon
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -25,53 +26,101 @@ const (
 	Dragon
 	Dark
 	Steel
+	Fairy
 )
 
+// Generation is a main series ruleset, used to tell which types and matchups exist.
+type Generation int
+
+const LatestGeneration Generation = 9
+
+type typeInfo struct {
+	name       string
+	introduced Generation
+}
+
+// Indexed by Type, RegisterType appends to it.
+var typeRegistry = []typeInfo{
+	{"Normal", 1}, {"Fire", 1}, {"Water", 1}, {"Electric", 1}, {"Grass", 1}, {"Ice", 1},
+	{"Fighting", 1}, {"Poison", 1}, {"Ground", 1}, {"Flying", 1}, {"Psychic", 1}, {"Bug", 1},
+	{"Rock", 1}, {"Ghost", 1}, {"Dragon", 1}, {"Dark", 2}, {"Steel", 2}, {"Fairy", 6},
+}
+
+// RegisterType adds a type beyond the built-in ones and returns its value.
+func RegisterType(name string, introduced Generation) (Type, error) {
+	if _, err := StringToPokemonType(name); err == nil {
+		return 0, fmt.Errorf("type %s already exists", name)
+	}
+	typeRegistry = append(typeRegistry, typeInfo{name: name, introduced: introduced})
+	return Type(len(typeRegistry) - 1), nil
+}
+
+// Types returns every known type.
+func Types() []Type {
+	types := make([]Type, len(typeRegistry))
+	for i := range typeRegistry {
+		types[i] = Type(i)
+	}
+	return types
+}
+
+// TypesForGeneration returns the types that exist in the given generation.
+func TypesForGeneration(gen Generation) []Type {
+	var types []Type
+	for i := range typeRegistry {
+		if Type(i).AvailableIn(gen) {
+			types = append(types, Type(i))
+		}
+	}
+	return types
+}
+
+func (t Type) valid() bool {
+	return t >= 0 && int(t) < len(typeRegistry)
+}
+
 func (t Type) String() string {
-	return [...]string{"Normal", "Fire", "Water", "Electric", "Grass", "Ice",
-		"Fighting", "Poison", "Ground", "Flying", "Psychic", "Bug", "Rock",
-		"Ghost", "Dragon", "Dark", "Steel"}[t]
+	if !t.valid() {
+		return fmt.Sprintf("Type(%d)", int(t))
+	}
+	return typeRegistry[t].name
+}
+
+// Generation returns the generation the type was introduced in.
+func (t Type) Generation() Generation {
+	if !t.valid() {
+		return 0
+	}
+	return typeRegistry[t].introduced
+}
+
+func (t Type) AvailableIn(gen Generation) bool {
+	return t.valid() && typeRegistry[t].introduced <= gen
+}
+
+func (t Type) MarshalText() ([]byte, error) {
+	if !t.valid() {
+		return nil, fmt.Errorf("invalid Pokemon type %d", int(t))
+	}
+	return []byte(t.String()), nil
+}
+
+func (t *Type) UnmarshalText(text []byte) error {
+	parsed, err := StringToPokemonType(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %q", err, text)
+	}
+	*t = parsed
+	return nil
 }
 
 func StringToPokemonType(typeStr string) (Type, error) {
-	switch strings.ToLower(typeStr) {
-	case "normal":
-		return Normal, nil
-	case "fire":
-		return Fire, nil
-	case "water":
-		return Water, nil
-	case "electric":
-		return Electric, nil
-	case "grass":
-		return Grass, nil
-	case "ice":
-		return Ice, nil
-	case "fighting":
-		return Fighting, nil
-	case "poison":
-		return Poison, nil
-	case "ground":
-		return Ground, nil
-	case "flying":
-		return Flying, nil
-	case "psychic":
-		return Psychic, nil
-	case "bug":
-		return Bug, nil
-	case "rock":
-		return Rock, nil
-	case "ghost":
-		return Ghost, nil
-	case "dragon":
-		return Dragon, nil
-	case "dark":
-		return Dark, nil
-	case "steel":
-		return Steel, nil
-	default:
-		return 0, errors.New("invalid Pokemon type")
+	for i, info := range typeRegistry {
+		if strings.EqualFold(info.name, typeStr) {
+			return Type(i), nil
+		}
 	}
+	return 0, errors.New("invalid Pokemon type")
 }
 
 type Nature int
@@ -101,33 +150,85 @@ const (
 	Relaxed
 	Sassy
 	Serious
-	Speed
+	Timid
 )
 
-var NatureInfo = map[Nature][2]string{
-	Adamant: {"Attack", "SpecialAttack"},
-	Bashful: {"", ""},
-	Bold:    {"Defense", "Attack"},
-	Brave:   {"Attack", "Speed"},
-	Calm:    {"SpecialDefense", "Attack"},
-	Careful: {"SpecialDefense", "SpecialAttack"},
-	Docile:  {"", ""},
-	Gentle:  {"SpecialDefense", "Defense"},
-	Hardy:   {"", ""},
-	Hasty:   {"Speed", "Defense"},
-	Impish:  {"Defense", "SpecialAttack"},
-	Jolly:   {"Speed", "SpecialAttack"},
-	Lax:     {"Defense", "SpecialDefense"},
-	Lonely:  {"Attack", "Defense"},
-	Mild:    {"SpecialAttack", "Defense"},
-	Modest:  {"SpecialAttack", "Attack"},
-	Naive:   {"Speed", "SpecialDefense"},
-	Naughty: {"Attack", "SpecialDefense"},
-	Quiet:   {"SpecialAttack", "Speed"},
-	Quirky:  {"", ""},
-	Rash:    {"SpecialAttack", "SpecialDefense"},
-	Relaxed: {"Defense", "Speed"},
-	Sassy:   {"SpecialDefense", "Speed"},
-	Serious: {"", ""},
-	Speed:   {"Speed", "Attack"},
+func (n Nature) String() string {
+	return [...]string{"Adamant", "Bashful", "Bold", "Brave", "Calm", "Careful", "Docile",
+		"Gentle", "Hardy", "Hasty", "Impish", "Jolly", "Lax", "Lonely", "Mild", "Modest",
+		"Naive", "Naughty", "Quiet", "Quirky", "Rash", "Relaxed", "Sassy", "Serious", "Timid"}[n]
+}
+
+// NatureEffect is the stat a nature raises by 10% and the one it lowers by 10%.
+// Neutral natures raise and lower the same stat, cancelling out.
+type NatureEffect struct {
+	Increased Stat
+	Decreased Stat
+}
+
+func (e NatureEffect) Neutral() bool {
+	return e.Increased == e.Decreased
+}
+
+var NatureInfo = map[Nature]NatureEffect{
+	Adamant: {StatAttack, StatSpecialAttack},
+	Bashful: {StatSpecialAttack, StatSpecialAttack},
+	Bold:    {StatDefense, StatAttack},
+	Brave:   {StatAttack, StatSpeed},
+	Calm:    {StatSpecialDefense, StatAttack},
+	Careful: {StatSpecialDefense, StatSpecialAttack},
+	Docile:  {StatDefense, StatDefense},
+	Gentle:  {StatSpecialDefense, StatDefense},
+	Hardy:   {StatAttack, StatAttack},
+	Hasty:   {StatSpeed, StatDefense},
+	Impish:  {StatDefense, StatSpecialAttack},
+	Jolly:   {StatSpeed, StatSpecialAttack},
+	Lax:     {StatDefense, StatSpecialDefense},
+	Lonely:  {StatAttack, StatDefense},
+	Mild:    {StatSpecialAttack, StatDefense},
+	Modest:  {StatSpecialAttack, StatAttack},
+	Naive:   {StatSpeed, StatSpecialDefense},
+	Naughty: {StatAttack, StatSpecialDefense},
+	Quiet:   {StatSpecialAttack, StatSpeed},
+	Quirky:  {StatSpecialDefense, StatSpecialDefense},
+	Rash:    {StatSpecialAttack, StatSpecialDefense},
+	Relaxed: {StatDefense, StatSpeed},
+	Sassy:   {StatSpecialDefense, StatSpeed},
+	Serious: {StatSpeed, StatSpeed},
+	Timid:   {StatSpeed, StatAttack},
+}
+
+func (n Nature) Effect() NatureEffect {
+	return NatureInfo[n]
+}
+
+// Multiplier returns how the nature scales the given stat: 1.1, 0.9 or 1.
+func (n Nature) Multiplier(stat Stat) float64 {
+	return float64(n.percent(stat)) / 100
+}
+
+func (n Nature) percent(stat Stat) int {
+	effect := n.Effect()
+	switch {
+	case effect.Neutral():
+		return 100
+	case effect.Increased == stat:
+		return 110
+	case effect.Decreased == stat:
+		return 90
+	default:
+		return 100
+	}
+}
+
+func Natures() []Nature {
+	natures := make([]Nature, 0, len(NatureInfo))
+	for n := Adamant; n <= Timid; n++ {
+		natures = append(natures, n)
+	}
+	return natures
+}
+
+func RandomNature() Nature {
+	return Nature(rng.Intn(int(Timid) + 1))
 }
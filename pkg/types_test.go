@@ -0,0 +1,162 @@
+package pokemon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStringToPokemonType(t *testing.T) {
+	for _, typ := range Types() {
+		got, err := StringToPokemonType(typ.String())
+		if err != nil || got != typ {
+			t.Errorf("StringToPokemonType(%q) = %v, %v, want %v", typ.String(), got, err, typ)
+		}
+	}
+
+	if got, err := StringToPokemonType("fairy"); err != nil || got != Fairy {
+		t.Errorf("StringToPokemonType(fairy) = %v, %v, want Fairy", got, err)
+	}
+
+	if _, err := StringToPokemonType("Sound"); err == nil {
+		t.Errorf("Expected error for unknown type, got nil")
+	}
+}
+
+func TestTypeJSON(t *testing.T) {
+	species := Species{Name: "Clefairy", Types: []Type{Fairy}}
+
+	data, err := json.Marshal(species.Types)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != `["Fairy"]` {
+		t.Errorf("json.Marshal() = %s, want [\"Fairy\"]", data)
+	}
+
+	var types []Type
+	if err := json.Unmarshal([]byte(`["grass","Poison"]`), &types); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(types, []Type{Grass, Poison}) {
+		t.Errorf("json.Unmarshal() = %v, want [Grass Poison]", types)
+	}
+
+	if err := json.Unmarshal([]byte(`[3]`), &types); err == nil {
+		t.Errorf("Expected error when unmarshalling a numeric type, got nil")
+	}
+}
+
+func TestRegisterType(t *testing.T) {
+	registered := len(typeRegistry)
+	t.Cleanup(func() { typeRegistry = typeRegistry[:registered] })
+
+	stellar, err := RegisterType("Stellar", 9)
+	if err != nil {
+		t.Fatalf("RegisterType() error = %v", err)
+	}
+
+	if got, err := StringToPokemonType("stellar"); err != nil || got != stellar {
+		t.Errorf("StringToPokemonType(stellar) = %v, %v, want %v", got, err, stellar)
+	}
+	if stellar.String() != "Stellar" || stellar.Generation() != 9 {
+		t.Errorf("registered type = %v from generation %d", stellar, stellar.Generation())
+	}
+
+	if _, err := RegisterType("Fire", 1); err == nil {
+		t.Errorf("Expected error registering an existing type, got nil")
+	}
+}
+
+func TestTypesForGeneration(t *testing.T) {
+	tests := []struct {
+		gen  Generation
+		want int
+	}{
+		{1, 15},
+		{2, 17},
+		{5, 17},
+		{6, 18},
+	}
+
+	for _, tt := range tests {
+		if got := TypesForGeneration(tt.gen); len(got) != tt.want {
+			t.Errorf("TypesForGeneration(%d) returned %d types, want %d", tt.gen, len(got), tt.want)
+		}
+	}
+
+	if Fairy.AvailableIn(5) || !Fairy.AvailableIn(6) {
+		t.Errorf("Fairy should only be available from generation 6")
+	}
+}
+
+func TestTypeChartForGeneration(t *testing.T) {
+	tests := []struct {
+		name      string
+		gen       Generation
+		attacking Type
+		defending []Type
+		want      float64
+	}{
+		{"gen 1 ghost cannot hit psychic", 1, Ghost, []Type{Psychic}, 0},
+		{"gen 1 bug beats poison", 1, Bug, []Type{Poison}, 2},
+		{"gen 2 ghost hits psychic", 2, Ghost, []Type{Psychic}, 2},
+		{"gen 5 steel resists dark", 5, Dark, []Type{Steel}, 0.5},
+		{"gen 5 has no fairy matchups", 5, Dragon, []Type{Fairy}, 1},
+		{"gen 6 steel no longer resists ghost", 6, Ghost, []Type{Steel}, 1},
+		{"gen 6 fairy is immune to dragon", 6, Dragon, []Type{Fairy}, 0},
+		{"gen 6 fairy beats dark/fighting", 6, Fairy, []Type{Dark, Fighting}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chart := TypeChartForGeneration(tt.gen)
+			if got := chart.Effectiveness(tt.attacking, tt.defending); got != tt.want {
+				t.Errorf("Effectiveness(%v, %v) = %v, want %v", tt.attacking, tt.defending, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNatureEffects(t *testing.T) {
+	if len(Natures()) != 25 {
+		t.Fatalf("Natures() returned %d natures, want 25", len(Natures()))
+	}
+
+	neutral := 0
+	for _, n := range Natures() {
+		effect := n.Effect()
+		if effect.Neutral() {
+			neutral++
+			if n.Multiplier(effect.Increased) != 1.0 {
+				t.Errorf("%v should not change %v", n, effect.Increased)
+			}
+			continue
+		}
+		if effect.Increased == StatHP || effect.Decreased == StatHP {
+			t.Errorf("%v should never affect HP", n)
+		}
+		if n.Multiplier(effect.Increased) != 1.1 || n.Multiplier(effect.Decreased) != 0.9 {
+			t.Errorf("%v multipliers = %v/%v, want 1.1/0.9", n, n.Multiplier(effect.Increased), n.Multiplier(effect.Decreased))
+		}
+	}
+	if neutral != 5 {
+		t.Errorf("Expected 5 neutral natures, got %d", neutral)
+	}
+
+	if effect := Timid.Effect(); effect.Increased != StatSpeed || effect.Decreased != StatAttack {
+		t.Errorf("Timid effect = %+v, want +Speed -Attack", effect)
+	}
+}
+
+func TestRandomNature(t *testing.T) {
+	setRandomizer(t, highRolls)
+	if got := RandomNature(); got != Timid {
+		t.Errorf("RandomNature() with the highest roll = %v, want Timid", got)
+	}
+
+	setRandomizer(t, &MockRand{IntnFunc: func(n int) int { return 0 }})
+	if got := RandomNature(); got != Adamant {
+		t.Errorf("RandomNature() with the lowest roll = %v, want Adamant", got)
+	}
+}
@@ -2,22 +2,37 @@ package pokemon
 
 import (
 	"encoding/json"
+	"math/rand"
 	"os"
 )
 
+// Randomizer is the source of every random roll in the package, so tests can
+// swap in something deterministic.
+type Randomizer interface {
+	Intn(n int) int
+}
+
+type mathRandomizer struct{}
+
+func (mathRandomizer) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+var rng Randomizer = mathRandomizer{}
+
 type FileIOHandler interface {
 	ReadFile(filename string) ([]byte, error)
 	WriteFile(filename string, data []byte, perm os.FileMode) error
 }
 
-type OSFileIOHandler struct {}
+type OSFileIOHandler struct{}
 
 func (h *OSFileIOHandler) ReadFile(filename string) ([]byte, error) {
-    return os.ReadFile(filename)
+	return os.ReadFile(filename)
 }
 
 func (h *OSFileIOHandler) WriteFile(filename string, data []byte, perm os.FileMode) error {
-    return os.WriteFile(filename, data, perm)
+	return os.WriteFile(filename, data, perm)
 }
 
 type MockFileIOHandler struct {
@@ -33,25 +48,25 @@ func (m *MockFileIOHandler) ReadFile(filename string) ([]byte, error) {
 	return data, nil
 }
 
-func (m *MockFileIOHandler) WriteFile(filename string, data[]byte, perm os.FileMode) error {
-    m.FileData[filename] = data
-    return nil
+func (m *MockFileIOHandler) WriteFile(filename string, data []byte, perm os.FileMode) error {
+	m.FileData[filename] = data
+	return nil
 }
 
 func SaveTOJSON(handler FileIOHandler, data interface{}, filename string) error {
-    jsonData, err := json.Marshal(data)
-    if err != nil {
-        return err
-    }
-    return handler.WriteFile(filename, jsonData, 0644)
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return handler.WriteFile(filename, jsonData, 0644)
 }
 
 func LoadFromJSON(handler FileIOHandler, filename string, data interface{}) error {
-    jsonData, err := handler.ReadFile(filename)
+	jsonData, err := handler.ReadFile(filename)
 
-    if err != nil {
-        return err
-    }
+	if err != nil {
+		return err
+	}
 
-    return json.Unmarshal(jsonData, data)
+	return json.Unmarshal(jsonData, data)
 }
@@ -0,0 +1,285 @@
+package pokemon
+
+const (
+	confusionHitChance    = 33 // percent of turns a confused Pokemon hurts itself
+	maxConfusionTurns     = 4
+	infatuationSkipChance = 50 // percent of turns an infatuated Pokemon can't move
+	minBoundTurns         = 4
+	maxBoundTurns         = 5
+	tauntTurns            = 3
+	encoreTurns           = 3
+)
+
+// FlinchStatus stops the Pokemon from moving for the rest of the turn. It only matters
+// when it was hit before getting to move.
+type FlinchStatus struct{}
+
+func (f *FlinchStatus) Volatile() {}
+
+func (f *FlinchStatus) BeforeAction(p *Pokemon, _ *BattleAction, log *BattleLog) bool {
+	log.Add("%s flinched and couldn't move!", p.Name())
+	return false
+}
+
+// OnTurnEnd wears flinching off.
+func (f *FlinchStatus) OnTurnEnd(p *Pokemon, _ *BattleLog) bool {
+	return false
+}
+
+func (f *FlinchStatus) Name() string {
+	return "Flinch"
+}
+
+// ConfusionStatus lasts Duration of the Pokemon's turns, on each it may hit itself
+// instead of using its move. Inflicted without a Duration it lasts 1 to 4 turns.
+type ConfusionStatus struct {
+	Duration int
+}
+
+func (c *ConfusionStatus) Volatile() {}
+
+func (c *ConfusionStatus) OnInflict(p *Pokemon) {
+	if c.Duration <= 0 {
+		c.Duration = rng.Intn(maxConfusionTurns) + 1
+	}
+}
+
+func (c *ConfusionStatus) BeforeAction(p *Pokemon, _ *BattleAction, log *BattleLog) bool {
+	if c.Duration <= 0 {
+		p.StatusManager.RemoveSecondary(c)
+		log.Add("%s snapped out of its confusion!", p.Name())
+		return true
+	}
+	c.Duration--
+
+	log.Add("%s is confused!", p.Name())
+	if rng.Intn(100) < confusionHitChance {
+		p.TakeDamage(confusionDamage(p))
+		log.Add("It hurt itself in its confusion!")
+		return false
+	}
+	return true
+}
+
+func (c *ConfusionStatus) Name() string {
+	return "Confusion"
+}
+
+// InfatuationStatus is Attract, which often stops the Pokemon from moving for as long
+// as Source is still standing and on the field.
+type InfatuationStatus struct {
+	Source *Pokemon
+}
+
+func (s *InfatuationStatus) Volatile() {}
+
+func (s *InfatuationStatus) BeforeAction(p *Pokemon, _ *BattleAction, log *BattleLog) bool {
+	if s.Source == nil || s.Source.Health.IsFainted() {
+		p.StatusManager.RemoveSecondary(s)
+		return true
+	}
+
+	log.Add("%s is in love with %s!", p.Name(), s.Source.Name())
+	if rng.Intn(100) < infatuationSkipChance {
+		log.Add("%s is immobilized by love!", p.Name())
+		return false
+	}
+	return true
+}
+
+func (s *InfatuationStatus) StatusSource() *Pokemon {
+	return s.Source
+}
+
+func (s *InfatuationStatus) Name() string {
+	return "Infatuation"
+}
+
+// canInfatuate reports whether two Pokemon have opposite genders, which Attract needs.
+func canInfatuate(user, target *Pokemon) bool {
+	return user.Gender != Genderless && target.Gender != Genderless && user.Gender != target.Gender
+}
+
+// LeechSeedStatus drains 1/8 of the Pokemon's max HP at the end of every turn, healing
+// Recipient by as much. It lasts until the seeded Pokemon leaves the field, a battle
+// keeps Recipient pointed at whichever Pokemon is active on the other side. Grass
+// types can't be seeded.
+type LeechSeedStatus struct {
+	Recipient *Pokemon
+}
+
+func (s *LeechSeedStatus) Volatile() {}
+
+func (s *LeechSeedStatus) OnTurnEnd(p *Pokemon, log *BattleLog) bool {
+	drained := min(atLeastOne(p.Health.Max/8), p.Health.Current)
+	p.TakeDamage(drained)
+	if s.Recipient != nil && !s.Recipient.Health.IsFainted() {
+		s.Recipient.Health.increase(drained)
+	}
+	log.Add("%s's health is sapped by Leech Seed!", p.Name())
+	return true
+}
+
+func (s *LeechSeedStatus) ImmuneTypes() []Type {
+	return []Type{Grass}
+}
+
+func (s *LeechSeedStatus) Name() string {
+	return "Leech Seed"
+}
+
+// seedRecipient points any Leech Seed on p at recipient.
+func (p *Pokemon) seedRecipient(recipient *Pokemon) {
+	for _, status := range p.StatusManager.Secondary {
+		if seed, ok := status.(*LeechSeedStatus); ok {
+			seed.Recipient = recipient
+		}
+	}
+}
+
+// BoundStatus is Bind, Wrap and the like: the Pokemon can't escape and takes 1/8 of its
+// max HP at the end of each of the 4 or 5 turns it lasts, or until Source faints or
+// leaves the field.
+type BoundStatus struct {
+	Source   *Pokemon
+	Duration int
+}
+
+func (s *BoundStatus) Volatile() {}
+
+func (s *BoundStatus) OnInflict(p *Pokemon) {
+	if s.Duration <= 0 {
+		s.Duration = minBoundTurns + rng.Intn(maxBoundTurns-minBoundTurns+1)
+	}
+}
+
+func (s *BoundStatus) OnTurnEnd(p *Pokemon, log *BattleLog) bool {
+	if s.Duration <= 0 || (s.Source != nil && s.Source.Health.IsFainted()) {
+		log.Add("%s was freed!", p.Name())
+		return false
+	}
+	s.Duration--
+	residualDamage(p, 1, 8)
+	log.Add("%s is hurt by the bind!", p.Name())
+	return true
+}
+
+func (s *BoundStatus) StatusSource() *Pokemon {
+	return s.Source
+}
+
+func (s *BoundStatus) Name() string {
+	return "Bound"
+}
+
+// Trapped reports whether the Pokemon is bound and so can't switch out or flee.
+func (p *Pokemon) Trapped() bool {
+	for _, status := range p.StatusManager.Secondary {
+		if _, bound := status.(*BoundStatus); bound {
+			return true
+		}
+	}
+	return false
+}
+
+// TauntStatus stops the Pokemon from using status moves for 3 turns.
+type TauntStatus struct {
+	Duration int
+}
+
+func (s *TauntStatus) Volatile() {}
+
+func (s *TauntStatus) OnInflict(p *Pokemon) {
+	if s.Duration <= 0 {
+		s.Duration = tauntTurns
+	}
+}
+
+func (s *TauntStatus) BeforeAction(p *Pokemon, action *BattleAction, log *BattleLog) bool {
+	if action.Move.Category == Status {
+		log.Add("%s can't use %s after the taunt!", p.Name(), action.Move.Name)
+		return false
+	}
+	return true
+}
+
+func (s *TauntStatus) OnTurnEnd(p *Pokemon, log *BattleLog) bool {
+	if s.Duration--; s.Duration <= 0 {
+		log.Add("%s's taunt wore off!", p.Name())
+		return false
+	}
+	return true
+}
+
+func (s *TauntStatus) Name() string {
+	return "Taunt"
+}
+
+// EncoreStatus makes the Pokemon use Move, the last move it used, for 3 turns. It ends
+// early once that move runs out of PP.
+type EncoreStatus struct {
+	Move     string
+	Duration int
+}
+
+func (s *EncoreStatus) Volatile() {}
+
+func (s *EncoreStatus) OnInflict(p *Pokemon) {
+	if s.Duration <= 0 {
+		s.Duration = encoreTurns
+	}
+}
+
+func (s *EncoreStatus) OverrideAction(p *Pokemon, action *BattleAction) {
+	if action.Type != Attack {
+		return
+	}
+	for i, slot := range p.Moves {
+		if slot.Move.Name == s.Move && slot.CanUse() {
+			action.MoveSlot = i
+			return
+		}
+	}
+	p.StatusManager.RemoveSecondary(s)
+}
+
+func (s *EncoreStatus) OnTurnEnd(p *Pokemon, log *BattleLog) bool {
+	if s.Duration--; s.Duration <= 0 {
+		log.Add("%s's encore ended!", p.Name())
+		return false
+	}
+	return true
+}
+
+func (s *EncoreStatus) Name() string {
+	return "Encore"
+}
+
+// SubstituteStatus is a decoy with HP of its own that takes the hits aimed at the
+// Pokemon, along with their secondary effects, until it breaks.
+type SubstituteStatus struct {
+	HP int
+}
+
+func (s *SubstituteStatus) Volatile() {}
+
+func (s *SubstituteStatus) Name() string {
+	return "Substitute"
+}
+
+// substitute is the Pokemon's substitute, or nil if it doesn't have one.
+func (p *Pokemon) substitute() *SubstituteStatus {
+	for _, status := range p.StatusManager.Secondary {
+		if sub, ok := status.(*SubstituteStatus); ok {
+			return sub
+		}
+	}
+	return nil
+}
+
+// absorb takes damage meant for p, removing the substitute once it breaks.
+func (s *SubstituteStatus) absorb(p *Pokemon, damage int) {
+	if s.HP -= damage; s.HP <= 0 {
+		p.StatusManager.RemoveSecondary(s)
+	}
+}
@@ -0,0 +1,287 @@
+package pokemon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newVolatileTestPokemon(name string, gender Gender) *Pokemon {
+	return &Pokemon{
+		Species: &Species{Name: name, Types: []Type{Normal}},
+		Level:   50,
+		Gender:  gender,
+		Health:  Health{Current: 160, Max: 160},
+		Stats:   Stats{Attack: 50, Defense: 50},
+	}
+}
+
+func TestVolatileBeforeAction(t *testing.T) {
+	growl := Move{Name: "Growl", Category: Status}
+	tackle := Move{Name: "Tackle", Category: Physical}
+	fainted := newVolatileTestPokemon("Eevee", Male)
+	fainted.Health.Current = 0
+
+	tests := []struct {
+		name      string
+		status    StatusEffect
+		move      Move
+		roll      int
+		want      bool
+		log       []string
+		remaining int
+	}{
+		{"immobilized by love", &InfatuationStatus{Source: newVolatileTestPokemon("Eevee", Male)}, tackle, 49, false,
+			[]string{"Pikachu is in love with Eevee!", "Pikachu is immobilized by love!"}, 1},
+		{"in love but moves", &InfatuationStatus{Source: newVolatileTestPokemon("Eevee", Male)}, tackle, 50, true,
+			[]string{"Pikachu is in love with Eevee!"}, 1},
+		{"love ends with its source", &InfatuationStatus{Source: fainted}, tackle, 0, true, nil, 0},
+		{"taunted status move", &TauntStatus{Duration: 3}, growl, 0, false, []string{"Pikachu can't use Growl after the taunt!"}, 1},
+		{"taunted attack", &TauntStatus{Duration: 3}, tackle, 0, true, nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRandomizer(t, &MockRand{IntnFunc: func(n int) int { return min(tt.roll, n-1) }})
+			pokemon := newVolatileTestPokemon("Pikachu", Female)
+			pokemon.StatusManager.Secondary = []StatusEffect{tt.status}
+
+			var log BattleLog
+			action := BattleAction{Type: Attack, Move: tt.move}
+			if got := pokemon.StatusManager.BeforeAction(pokemon, &action, &log); got != tt.want {
+				t.Errorf("BeforeAction() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(log.Messages, tt.log) {
+				t.Errorf("log = %q, want %q", log.Messages, tt.log)
+			}
+			if got := len(pokemon.StatusManager.Secondary); got != tt.remaining {
+				t.Errorf("%d statuses left, want %d", got, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestVolatileTurnEnd(t *testing.T) {
+	source := newVolatileTestPokemon("Bulbasaur", Male)
+	source.Health.Current = 100
+
+	tests := []struct {
+		name   string
+		status StatusEffect
+		health int
+		log    []string
+		kept   bool
+	}{
+		{"leech seed", &LeechSeedStatus{Recipient: source}, 140, []string{"Pikachu's health is sapped by Leech Seed!"}, true},
+		{"bound", &BoundStatus{Duration: 2}, 140, []string{"Pikachu is hurt by the bind!"}, true},
+		{"freed", &BoundStatus{}, 160, []string{"Pikachu was freed!"}, false},
+		{"taunt wears off", &TauntStatus{Duration: 1}, 160, []string{"Pikachu's taunt wore off!"}, false},
+		{"taunt lasts", &TauntStatus{Duration: 2}, 160, nil, true},
+		{"encore ends", &EncoreStatus{Move: "Tackle", Duration: 1}, 160, []string{"Pikachu's encore ended!"}, false},
+		{"flinch wears off", &FlinchStatus{}, 160, nil, false},
+		{"confusion lasts", &ConfusionStatus{Duration: 2}, 160, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pokemon := newVolatileTestPokemon("Pikachu", Female)
+			pokemon.StatusManager.Secondary = []StatusEffect{tt.status}
+
+			var log BattleLog
+			pokemon.StatusManager.TurnEnd(pokemon, &log)
+
+			if pokemon.Health.Current != tt.health {
+				t.Errorf("health = %d, want %d", pokemon.Health.Current, tt.health)
+			}
+			if !reflect.DeepEqual(log.Messages, tt.log) {
+				t.Errorf("log = %q, want %q", log.Messages, tt.log)
+			}
+			if kept := len(pokemon.StatusManager.Secondary) == 1; kept != tt.kept {
+				t.Errorf("status kept = %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+
+	if source.Health.Current != 120 {
+		t.Errorf("Leech Seed should heal its recipient by what it drained, health = %d", source.Health.Current)
+	}
+}
+
+func TestVolatileSecondaryEffects(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		user   *Pokemon
+		target func() *Pokemon
+		want   string // the target's volatile status afterwards, "" for none
+	}{
+		{"confusion", "confusion", newVolatileTestPokemon("Golbat", Male), func() *Pokemon { return newVolatileTestPokemon("Pikachu", Female) }, "Confusion"},
+		{"attract", "infatuation", newVolatileTestPokemon("Eevee", Male), func() *Pokemon { return newVolatileTestPokemon("Pikachu", Female) }, "Infatuation"},
+		{"attract same gender", "infatuation", newVolatileTestPokemon("Eevee", Female), func() *Pokemon { return newVolatileTestPokemon("Pikachu", Female) }, ""},
+		{"attract genderless", "infatuation", newVolatileTestPokemon("Magnemite", Genderless), func() *Pokemon { return newVolatileTestPokemon("Pikachu", Female) }, ""},
+		{"leech seed", "leech-seed", newVolatileTestPokemon("Bulbasaur", Male), func() *Pokemon { return newVolatileTestPokemon("Pikachu", Female) }, "Leech Seed"},
+		{"grass types can't be seeded", "leech-seed", newVolatileTestPokemon("Bulbasaur", Male), func() *Pokemon { return newDamageTestPokemon([]Type{Grass}) }, ""},
+		{"wrap", "trap", newVolatileTestPokemon("Ekans", Male), func() *Pokemon { return newVolatileTestPokemon("Pikachu", Female) }, "Bound"},
+		{"encore", "encore", newVolatileTestPokemon("Clefairy", Female), func() *Pokemon {
+			target := newVolatileTestPokemon("Pikachu", Female)
+			target.lastMove = "Tackle"
+			return target
+		}, "Encore"},
+		{"encore before any move", "encore", newVolatileTestPokemon("Clefairy", Female), func() *Pokemon { return newVolatileTestPokemon("Pikachu", Female) }, ""},
+		{"substitute blocks it", "confusion", newVolatileTestPokemon("Golbat", Male), func() *Pokemon {
+			target := newVolatileTestPokemon("Pikachu", Female)
+			target.StatusManager.Secondary = []StatusEffect{&SubstituteStatus{HP: 40}}
+			return target
+		}, "Substitute"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRandomizer(t, highRolls)
+			move := newEffectMove(t, 0, spec("status", `{"status": "`+tt.status+`"}`))
+			target := tt.target()
+
+			move.Execute(tt.user, target)
+
+			got := ""
+			if secondary := target.StatusManager.Secondary; len(secondary) > 0 {
+				got = secondary[len(secondary)-1].Name()
+			}
+			if got != tt.want {
+				t.Errorf("target status = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	setRandomizer(t, highRolls)
+	user := newDamageTestPokemon([]Type{Normal})
+	substitute := newEffectMove(t, 0, spec("substitute", ``))
+
+	substitute.Execute(user, newDamageTestPokemon([]Type{Normal}))
+	sub := user.substitute()
+	if sub == nil || sub.HP != 50 || user.Health.Current != 150 {
+		t.Fatalf("Substitute should cost a quarter of max HP, got %+v at %d", sub, user.Health.Current)
+	}
+	substitute.Execute(user, newDamageTestPokemon([]Type{Normal}))
+	if user.Health.Current != 150 {
+		t.Errorf("A second substitute shouldn't cost anything, health = %d", user.Health.Current)
+	}
+
+	attacker := newDamageTestPokemon([]Type{Fire})
+	strike := Move{Name: "Strike", Type: Normal, Category: Physical, Power: 80, Accuracy: 100}
+	result := strike.Execute(attacker, user)
+	if !result.Substitute || user.Health.Current != 150 || sub.HP != 50-37 {
+		t.Errorf("The substitute should take the hit, result %+v, health %d, substitute HP %d", result, user.Health.Current, sub.HP)
+	}
+
+	strike.Execute(attacker, user)
+	if user.substitute() != nil || user.Health.Current != 150 {
+		t.Errorf("The substitute should break without passing damage on, health %d", user.Health.Current)
+	}
+	strike.Execute(attacker, user)
+	if user.Health.Current != 150-37 {
+		t.Errorf("Hits should land once the substitute broke, health %d", user.Health.Current)
+	}
+
+	weak := newDamageTestPokemon([]Type{Normal})
+	weak.Health.Current = 50
+	substitute.Execute(weak, user)
+	if weak.substitute() != nil || weak.Health.Current != 50 {
+		t.Errorf("Substitute should fail without more than a quarter of max HP left")
+	}
+}
+
+func TestBattleEncore(t *testing.T) {
+	setRandomizer(t, highRolls)
+	tackle := Move{Name: "Tackle", Category: Physical, Power: 40, PP: 35, Accuracy: 100}
+	quickAttack := Move{Name: "Quick Attack", Category: Physical, Power: 40, PP: 30, Accuracy: 100, Priority: 1}
+	encored := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 50}, Moves: NewMoveSlots([4]Move{tackle, quickAttack})}
+	other := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Attack: 20, Defense: 20, Speed: 90}, Moves: NewMoveSlots([4]Move{tackle})}
+	encored.StatusManager.Secondary = []StatusEffect{&EncoreStatus{Move: "Quick Attack", Duration: 3}}
+
+	battle := NewBattle(
+		&MockBattler{Pokemon: encored, Action: BattleAction{Type: Attack, MoveSlot: 0}},
+		&MockBattler{Pokemon: other, Action: BattleAction{Type: Attack}},
+	)
+	battle.MaxTurns = 1
+	battle.Run()
+
+	if encored.Moves[1].PP != 29 || encored.Moves[0].PP != 35 {
+		t.Errorf("Encore should force Quick Attack, PP %d and %d", encored.Moves[0].PP, encored.Moves[1].PP)
+	}
+	if order := moveOrder(battle.Log); !reflect.DeepEqual(order, []string{"Charmander", "Bulbasaur"}) {
+		t.Errorf("The encored Quick Attack should go first, order %v", order)
+	}
+	if battle.Log.Messages[0] != "Charmander used Quick Attack!" {
+		t.Errorf("log = %q, want Charmander to use Quick Attack first", battle.Log.Messages)
+	}
+}
+
+func TestTrapped(t *testing.T) {
+	pokemon := newVolatileTestPokemon("Pikachu", Female)
+	if pokemon.Trapped() {
+		t.Errorf("Trapped() without a bind = true")
+	}
+	pokemon.StatusManager.Secondary = []StatusEffect{&BoundStatus{Duration: 4}}
+	if !pokemon.Trapped() {
+		t.Errorf("Trapped() while bound = false")
+	}
+	pokemon.StatusManager.SwitchOut(pokemon)
+	if pokemon.Trapped() {
+		t.Errorf("Switching out should clear the bind")
+	}
+}
+
+func TestLeechSeedHealsReplacement(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	splash := Move{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}
+	seeder := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 50, Max: 100}, Stats: Stats{Speed: 50}}
+	bench := &Pokemon{Species: &Species{Name: "Squirtle"}, Level: 10, Health: Health{Current: 50, Max: 100}, Stats: Stats{Speed: 50}}
+	target := &Pokemon{Species: &Species{Name: "Pidgey"}, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 90}, Moves: NewMoveSlots([4]Move{splash})}
+	target.StatusManager.Secondary = []StatusEffect{&LeechSeedStatus{Recipient: seeder}}
+
+	trainer := &ScriptedTrainer{Trainer: NewTrainer("Misty", [TeamSize]*Pokemon{seeder, bench}), Action: BattleAction{Type: SwitchPokemon, SwitchTo: 1}}
+	battle := NewBattle(trainer, &MockBattler{Pokemon: target, Action: BattleAction{Type: Attack}})
+	battle.MaxTurns = 1
+	battle.Run()
+
+	if target.Health.Current != 88 || seeder.Health.Current != 50 || bench.Health.Current != 62 {
+		t.Errorf("Leech Seed should keep draining and heal the replacement, health %d, seeder %d, bench %d", target.Health.Current, seeder.Health.Current, bench.Health.Current)
+	}
+}
+
+func TestSourcedStatusesEndWhenSourceLeaves(t *testing.T) {
+	setRandomizer(t, highRolls)
+
+	tests := []struct {
+		name   string
+		status func(source *Pokemon) StatusEffect
+	}{
+		{"bind", func(source *Pokemon) StatusEffect { return &BoundStatus{Source: source, Duration: 4} }},
+		{"attract", func(source *Pokemon) StatusEffect { return &InfatuationStatus{Source: source} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			splash := Move{Name: "Splash", Category: Status, PP: 40, Accuracy: 100}
+			source := &Pokemon{Species: CharmanderSpecies, Level: 10, Health: Health{Current: 50, Max: 100}, Stats: Stats{Speed: 50}}
+			bench := &Pokemon{Species: &Species{Name: "Squirtle"}, Level: 10, Health: Health{Current: 50, Max: 100}, Stats: Stats{Speed: 50}}
+			target := &Pokemon{Species: BulbasaurSpecies, Level: 10, Health: Health{Current: 100, Max: 100}, Stats: Stats{Speed: 90}, Moves: NewMoveSlots([4]Move{splash})}
+			target.StatusManager.Secondary = []StatusEffect{tt.status(source)}
+
+			trainer := &ScriptedTrainer{Trainer: NewTrainer("Misty", [TeamSize]*Pokemon{source, bench}), Action: BattleAction{Type: SwitchPokemon, SwitchTo: 1}}
+			battle := NewBattle(trainer, &MockBattler{Pokemon: target, Action: BattleAction{Type: Attack}})
+			battle.MaxTurns = 1
+			battle.Run()
+
+			if target.Health.Current != 100 || source.Health.Current != 50 || bench.Health.Current != 50 {
+				t.Errorf("status kept going after its source left, health %d, source %d, bench %d", target.Health.Current, source.Health.Current, bench.Health.Current)
+			}
+			if want := []string{"Come back, Charmander!", "Go! Squirtle!", "Bulbasaur used Splash!"}; !reflect.DeepEqual(battle.Log.Messages, want) {
+				t.Errorf("log = %q, want %q", battle.Log.Messages, want)
+			}
+		})
+	}
+}